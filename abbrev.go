@@ -0,0 +1,201 @@
+package funcv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AbbreviationError is returned when an abbreviated constant or
+// long flag is a prefix of more than one of its siblings
+type AbbreviationError struct {
+	Token      string   // the abbreviated token
+	Candidates []string // the constants or flags the token is a prefix of
+}
+
+func (e *AbbreviationError) Error() string {
+	return fmt.Sprintf("funcv: ambiguous abbreviation %q matches %s", e.Token, quoteAll(e.Candidates))
+}
+
+// EnableAbbreviations makes the group accept a prefix of a constant
+// in the leading constants of its commands (the constants before the
+// first other argument), or of a long flag, as long as the prefix is
+// unique among the constants at the same position of the commands
+// that matched the previous constants, or among the long flags of
+// these commands, an ambiguous prefix fails with an *AbbreviationError,
+// a token is not expanded into a constant if one of these commands
+// accepts other arguments at its position (ex: a variable)
+func (g *Group) EnableAbbreviations() *Group {
+	g.abbreviations = true
+	return g
+}
+
+// leadingConstants returns the constants at the start of the command
+func leadingConstants(cmd Command) []*constant {
+	c, ok := cmd.(*command)
+
+	if !ok {
+		return nil
+	}
+
+	var constants []*constant
+
+	for _, arg := range c.args {
+		ct, ok := arg.(*constant)
+
+		if !ok {
+			break
+		}
+
+		constants = append(constants, ct)
+	}
+
+	return constants
+}
+
+// matches returns true if the token is the constant's text
+func (c *constant) matches(token string) bool {
+	if c.insensitive {
+		return strings.EqualFold(token, c.text)
+	}
+
+	return token == c.text
+}
+
+// isPrefix returns true if the token is a prefix of the constant's text
+func (c *constant) isPrefix(token string) bool {
+	if c.insensitive {
+		return len(token) <= len(c.text) && strings.EqualFold(token, c.text[:len(token)])
+	}
+
+	return strings.HasPrefix(c.text, token)
+}
+
+// expand returns a copy of the arguments with the abbreviated
+// constants and long flags replaced by their full names
+func (g *Group) expand(args []string) ([]string, error) {
+	expanded := append([]string(nil), args...)
+
+	alive := make([]*command, 0, len(g.pairs))
+
+	for _, p := range g.pairs {
+		if c, ok := p.Cmd.(*command); ok {
+			alive = append(alive, c)
+		}
+	}
+
+	for i, token := range expanded {
+		var siblings []*constant
+
+		open := false
+
+		for _, c := range alive {
+			switch lc := leadingConstants(c); {
+			case len(lc) > i:
+				siblings = append(siblings, lc[i])
+			case len(c.args) > len(lc):
+				open = true
+			}
+		}
+
+		if len(siblings) == 0 {
+			break
+		}
+
+		text := token
+
+		if !open {
+			var err error
+
+			if text, err = abbreviated(token, siblings); err != nil {
+				return args, err
+			}
+		}
+
+		expanded[i] = text
+
+		var next []*command
+
+		for _, c := range alive {
+			if lc := leadingConstants(c); len(lc) <= i || lc[i].matches(text) {
+				next = append(next, c)
+			}
+		}
+
+		alive = next
+	}
+
+	var flags []string
+
+	seen := make(map[string]bool)
+
+	for _, c := range alive {
+		for _, arg := range c.args {
+			if fb, ok := arg.(*flagsBuilder); ok {
+				for _, name := range fb.flags {
+					if len(name) > 1 && !seen[name] {
+						seen[name] = true
+						flags = append(flags, name)
+					}
+				}
+			}
+		}
+	}
+
+	for i, token := range expanded {
+		if !strings.HasPrefix(token, "--") {
+			continue
+		}
+
+		name := extractFlagName(token)
+
+		if name == "" || seen[name] || g.outputFlag && name == "output" {
+			continue
+		}
+
+		var candidates []string
+
+		for _, flag := range flags {
+			if strings.HasPrefix(flag, name) {
+				candidates = append(candidates, toFlag(flag))
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+		case 1:
+			expanded[i] = candidates[0]
+		default:
+			return args, &AbbreviationError{Token: token, Candidates: candidates}
+		}
+	}
+
+	return expanded, nil
+}
+
+// abbreviated returns the text of the constant the token matches
+// or abbreviates, or the token itself if it matches none
+func abbreviated(token string, siblings []*constant) (string, error) {
+	var candidates []string
+
+	seen := make(map[string]bool)
+
+	for _, c := range siblings {
+		if c.matches(token) {
+			return token, nil
+		}
+
+		if token != "" && c.isPrefix(token) && !seen[c.text] {
+			seen[c.text] = true
+			candidates = append(candidates, c.text)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return token, nil
+	case 1:
+		return candidates[0], nil
+	}
+
+	return token, &AbbreviationError{Token: token, Candidates: candidates}
+}
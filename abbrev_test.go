@@ -0,0 +1,117 @@
+package funcv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAbbreviations(t *testing.T) {
+	var grp Group
+	var called string
+	var verbose bool
+
+	grp.EnableAbbreviations()
+
+	for _, name := range []string{"status", "start", "stop", "STASH"} {
+		name := name
+
+		if err := NewCommand(name).
+			AddConstant("tool", false).
+			AddConstant(name, name == "STASH").
+			AddParameterlessFlag("verbose", "", new(BooleanConverter), true, false).
+			AddParameterlessFlag("version", "", new(BooleanConverter), true, false).
+			AddParameterlessFlag("v", "", new(BooleanConverter), true, false).
+			ToGroup(&grp, func(v, ver, short bool) {
+				called, verbose = name, v
+			}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]string{
+		"tool stat":         "status",
+		"tool star":         "start",
+		"to sto":            "stop",
+		"tool stas":         "STASH",
+		"tool stop":         "stop",
+		"tool start --verb": "start",
+	}
+
+	for args, expected := range cases {
+		called, verbose = "", false
+
+		if i, err := grp.ExecuteBest(strings.Fields(args)); err != nil || i < 0 || called != expected {
+			t.Fatal(args, i, err, called)
+		}
+
+		if strings.Contains(args, "--verb") != verbose {
+			t.Fatal(args, verbose)
+		}
+	}
+
+	for args, candidates := range map[string]string{"tool st": `"status" or "start" or "stop" or "STASH"`, "tool sta": `"status" or "start" or "STASH"`, "tool stop --ver": `"--verbose" or "--version"`} {
+		_, err := grp.ExecuteBest(strings.Fields(args))
+
+		var ae *AbbreviationError
+
+		if !errors.As(err, &ae) || !strings.HasSuffix(err.Error(), candidates) {
+			t.Fatal(args, err)
+		}
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "st"}) >= 0 {
+		t.FailNow()
+	}
+
+	var errOut bytes.Buffer
+
+	if grp.SetErrorOutput(&errOut).Main([]string{"tool", "st"}) != ExitUsage || !strings.Contains(errOut.String(), "ambiguous abbreviation") {
+		t.Fatal(errOut.String())
+	}
+}
+
+func TestAbbreviationsDisabled(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("status", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"stat"}) >= 0 {
+		t.FailNow()
+	}
+}
+
+func TestAbbreviationsWithVariables(t *testing.T) {
+	var grp Group
+	var called, name string
+	var all bool
+
+	grp.EnableAbbreviations()
+
+	if err := NewCommand("").AddConstant("get", false).AddConstant("status", false).ToGroup(&grp, func() {
+		called = "status"
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("get", false).AddParameterlessFlag("all", "", new(BooleanConverter), true, false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(a bool, n string) {
+		called, all, name = "name", a, n
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst(strings.Fields("ge sta")); i != 1 || called != "name" || name != "sta" {
+		t.Fatal(i, called, name)
+	}
+
+	if i := grp.ExecuteFirst(strings.Fields("get status")); i != 0 || called != "status" {
+		t.Fatal(i, called)
+	}
+
+	if i := grp.ExecuteFirst(strings.Fields("get --al x")); i != 1 || !all || name != "x" {
+		t.Fatal(i, all, name)
+	}
+}
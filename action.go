@@ -0,0 +1,173 @@
+package funcv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// binder resolves the action function parameters that are
+// injected rather than receiving the command's arguments, the
+// registries are searched in their order
+type binder struct {
+	ctx        context.Context
+	cmd        Command
+	registries []*Registry
+}
+
+// injects returns true if parameters of the given type are injected
+func (b *binder) injects(t reflect.Type) (bool, error) {
+	_, found, err := b.lookup(t)
+	return found, err
+}
+
+func (b *binder) lookup(t reflect.Type) (reflect.Value, bool, error) {
+	switch t {
+	case contextType:
+		ctx := b.ctx
+
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return reflect.ValueOf(&ctx).Elem(), true, nil
+	case commandType:
+		return reflect.ValueOf(&b.cmd).Elem(), b.cmd != nil, nil
+	case flagsType:
+		return reflect.ValueOf(flagsFrom(b.ctx)), true, nil
+	}
+
+	for _, r := range b.registries {
+		if v, found, err := r.lookup(t); found || err != nil {
+			return v, found, err
+		}
+	}
+
+	return reflect.Value{}, false, nil
+}
+
+// value returns the injected value of the given type
+func (b *binder) value(t reflect.Type) reflect.Value {
+	v, _, _ := b.lookup(t)
+	return v
+}
+
+// argIndexes returns the indexes of the function parameters that
+// receive the command's arguments, the variadic parameter is never
+// injected
+func (b *binder) argIndexes(t reflect.Type) ([]int, error) {
+	var idx []int
+
+	for i := 0; i < t.NumIn(); i++ {
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			idx = append(idx, i)
+			continue
+		}
+
+		injected, err := b.injects(t.In(i))
+
+		if err != nil {
+			return nil, fmt.Errorf("funcv: function param %d (%w)", i, err)
+		}
+
+		if !injected {
+			idx = append(idx, i)
+		}
+	}
+
+	return idx, nil
+}
+
+// bind returns the action function's input values, the injected
+// values and the extracted parameters converted to their types
+func (c *command) bind(t reflect.Type, b *binder, names []string, params []interface{}) ([]reflect.Value, error) {
+	idx, err := b.argIndexes(t)
+
+	if err != nil {
+		return nil, err
+	}
+
+	in := make([]reflect.Value, t.NumIn())
+
+	for i := range in {
+		if !contains(idx, i) {
+			in[i] = b.value(t.In(i))
+		}
+	}
+
+	if c.structType != nil && len(idx) == 1 && !t.IsVariadic() && isStructParam(t.In(idx[0]), c.structType) {
+		v, err := c.structParam(t.In(idx[0]), names, params)
+
+		if err != nil {
+			return nil, err
+		}
+
+		in[idx[0]] = v
+
+		return in, nil
+	}
+
+	return bindParams(t, idx, in, names, params)
+}
+
+// bindParams converts the extracted parameters to the types of the
+// function parameters at the idx indexes and sets them in the input
+func bindParams(t reflect.Type, idx []int, in []reflect.Value, names []string, params []interface{}) ([]reflect.Value, error) {
+	count := len(idx)
+
+	if count != len(params) {
+
+		if !t.IsVariadic() {
+			return nil, fmt.Errorf("funcv: invalid function params count [count: %d, input: %d]", count, len(params))
+		}
+
+		if len(params) < count-1 {
+			return nil, fmt.Errorf("funcv: invalid variadic function params count [count: %d..inf, input=%d]", count-1, len(params))
+		}
+	}
+
+	var rest []reflect.Value
+
+	k := 0
+
+	for j, param := range params {
+		i := idx[k]
+		pt := t.In(i)
+
+		if k+1 == count && t.IsVariadic() {
+			pt = pt.Elem()
+		} else {
+			k++
+		}
+
+		cv, err := convertParam(reflect.ValueOf(param), pt)
+
+		if err != nil {
+			return nil, fmt.Errorf("funcv: param %s: %w", names[j], err)
+		}
+
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			rest = append(rest, cv)
+		} else {
+			in[i] = cv
+		}
+	}
+
+	if t.IsVariadic() {
+		in = append(in[:t.NumIn()-1], rest...)
+	}
+
+	return in, nil
+}
+
+func contains(idx []int, i int) bool {
+	for _, j := range idx {
+		if j == i {
+			return true
+		}
+	}
+
+	return false
+}
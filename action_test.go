@@ -0,0 +1,118 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func TestExecuteContext(t *testing.T) {
+	c := NewCommand("").AddConstant("test", false).AddVariable("v", "", new(IntegerConverter)).MustCompile()
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	var got interface{}
+	var v int
+
+	_, err := c.ExecuteContext(ctx, []string{"test", "5"}, func(ctx context.Context, a int) {
+		got, v = ctx.Value(ctxKey{}), a
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "value" || v != 5 {
+		t.Fatal("wrong values", got, v)
+	}
+
+	if _, err := c.Execute([]string{"test", "5"}, func(ctx context.Context, a int) {
+		if ctx == nil {
+			t.Fatal("nil context")
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteContextCanceled(t *testing.T) {
+	c := NewCommand("").AddConstant("test", false).MustCompile()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.ExecuteContext(ctx, []string{"test"}, func() {
+		t.Fatal("func called")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteContextVariadic(t *testing.T) {
+	fn := func(ctx context.Context, v ...string) {
+		if len(v) != 2 {
+			t.Fatal("wrong values", v)
+		}
+	}
+
+	c, err := NewCommand("").AddVariadic("v", "", new(StringConverter)).CompileFor(fn)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.ExecuteContext(context.Background(), []string{"a", "b"}, fn)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGroupContext(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("cmd", false).ToGroup(&grp, func(ctx context.Context) error {
+		return ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirstContext(context.Background(), []string{"cmd"}) != 0 {
+		t.FailNow()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if grp.ExecuteAllContext(ctx, []string{"cmd"}) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestGroupFirstActionError(t *testing.T) {
+	var grp Group
+	var calls []string
+
+	errFail := errors.New("fail")
+
+	if err := NewCommand("a").AddConstant("run", false).ToGroup(&grp, func() error {
+		calls = append(calls, "a")
+		return errFail
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("b").AddConstant("run", false).ToGroup(&grp, func() {
+		calls = append(calls, "b")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i, err := grp.ExecuteFirstErr([]string{"run"}); i != 0 || err != errFail || len(calls) != 1 {
+		t.Fatal(i, err, calls)
+	}
+}
@@ -0,0 +1,224 @@
+package funcv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxAliasDepth limits the nested expansions of aliases
+const maxAliasDepth = 32
+
+// Alias of a command, when the alias's name is given where a
+// command is expected (see AddAlias), it's replaced by the expansion
+type Alias struct {
+	Name      string
+	Expansion []string
+}
+
+func (a Alias) String() string {
+	return fmt.Sprintf("%s = %s", a.Name, strings.Join(a.Expansion, " "))
+}
+
+// AddAlias adds an alias to the group, the expansion replaces the
+// alias's name when it's the first token that doesn't continue the
+// leading constants of the group's commands (ex: "deploy-prod" in
+// "tool deploy-prod" if "tool" is a leading constant), the tokens
+// after the name are appended to the expansion unless it refers to
+// them by the placeholders $1, $2, ... (a single token, the tokens
+// after the last referred one are appended) or $@ (all the tokens),
+// an expansion can refer to other aliases, defining an alias that
+// any token of its expansion leads back to fails
+func (g *Group) AddAlias(name string, expansion ...string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return fmt.Errorf("funcv: invalid alias name %q", name)
+	}
+
+	if len(expansion) == 0 {
+		return fmt.Errorf("funcv: empty alias %s", name)
+	}
+
+	if _, found := g.aliases[name]; found {
+		return fmt.Errorf("funcv: alias %s is already defined", name)
+	}
+
+	if chain := g.cycle([]string{name}, expansion); chain != nil {
+		return fmt.Errorf("funcv: recursive alias %s", strings.Join(chain, " -> "))
+	}
+
+	if g.aliases == nil {
+		g.aliases = make(map[string]Alias)
+	}
+
+	g.aliases[name] = Alias{Name: name, Expansion: append([]string(nil), expansion...)}
+
+	return nil
+}
+
+// cycle returns the chain of aliases from the first alias in the
+// given chain back to it, through the tokens of the expansion of
+// the last alias in the chain that name aliases, or nil if there
+// is no such chain
+func (g *Group) cycle(chain []string, expansion []string) []string {
+	for _, token := range expansion {
+		if token == chain[0] {
+			return append(chain, token)
+		}
+
+		a, found := g.aliases[token]
+
+		if !found || slices.Contains(chain, token) {
+			continue
+		}
+
+		if cycle := g.cycle(append(chain, token), a.Expansion); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// LoadAliases adds the aliases that are defined in the reader, a
+// line per alias, "name = expansion" (optionally preceded by "alias"),
+// the expansion's tokens are separated by white space, empty lines
+// and lines starting with # are ignored
+func (g *Group) LoadAliases(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, expansion, found := strings.Cut(line, "=")
+
+		if !found {
+			return fmt.Errorf("funcv: invalid alias at line %d", n)
+		}
+
+		name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "alias "))
+
+		if err := g.AddAlias(name, strings.Fields(expansion)...); err != nil {
+			return fmt.Errorf("funcv: invalid alias at line %d (%w)", n, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Aliases returns the group's aliases sorted by name
+func (g *Group) Aliases() []Alias {
+	aliases := make([]Alias, 0, len(g.aliases))
+
+	for _, a := range g.aliases {
+		aliases = append(aliases, a)
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Name < aliases[j].Name
+	})
+
+	return aliases
+}
+
+// unalias returns a copy of the arguments with the alias, if
+// there is one where a command is expected, replaced by its expansion
+func (g *Group) unalias(args []string) ([]string, error) {
+	if len(g.aliases) == 0 {
+		return args, nil
+	}
+
+	for depth := 0; ; depth++ {
+		d := 0
+
+		if g.index != nil {
+			d, _ = g.index.deepest(args)
+		}
+
+		if d >= len(args) {
+			return args, nil
+		}
+
+		a, found := g.aliases[args[d]]
+
+		if !found {
+			return args, nil
+		}
+
+		if depth == maxAliasDepth {
+			return args, fmt.Errorf("funcv: alias %s is nested too deep", a.Name)
+		}
+
+		expansion, err := a.expand(args[d+1:])
+
+		if err != nil {
+			return args, err
+		}
+
+		args = append(append([]string(nil), args[:d]...), expansion...)
+	}
+}
+
+// expand returns the alias's expansion with the placeholders
+// replaced by the given tokens, followed by the tokens after the
+// last one that a $N placeholder refers to, or by all of them if
+// there are no placeholders
+func (a Alias) expand(tokens []string) ([]string, error) {
+	var expanded []string
+
+	placeholders, all, last := false, false, 0
+
+	for _, t := range a.Expansion {
+		switch {
+		case t == "$@":
+			placeholders, all = true, true
+			expanded = append(expanded, tokens...)
+		case len(t) > 1 && t[0] == '$':
+			i, err := strconv.Atoi(t[1:])
+
+			if err != nil || i < 1 {
+				expanded = append(expanded, t)
+				continue
+			}
+
+			if i > len(tokens) {
+				return nil, fmt.Errorf("funcv: alias %s expects at least %d arguments", a.Name, i)
+			}
+
+			placeholders, last = true, max(last, i)
+			expanded = append(expanded, tokens[i-1])
+		default:
+			expanded = append(expanded, t)
+		}
+	}
+
+	switch {
+	case !placeholders:
+		expanded = append(expanded, tokens...)
+	case !all:
+		expanded = append(expanded, tokens[last:]...)
+	}
+
+	return expanded, nil
+}
+
+// writeAliases will write to the writer the group's aliases
+func (g *Group) writeAliases(w io.Writer, aliases []Alias) (int64, error) {
+	var sb strings.Builder
+
+	sb.WriteString("aliases:")
+
+	for _, a := range aliases {
+		sb.WriteString(fmt.Sprintf("\n\t%s\t= %s", a.Name, strings.Join(a.Expansion, " ")))
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
@@ -0,0 +1,310 @@
+package funcv
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("deploy-prod", "deploy", "--env", "prod", "--confirm"); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "deploy-prod", "api", "web"}) != 0 || !reflect.DeepEqual(called, []string{"prod", "true", "api", "web"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasOfAlias(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("deploy-prod", "deploy", "--env", "prod", "--confirm"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("dp", "deploy-prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "dp", "api"}) != 0 || !reflect.DeepEqual(called, []string{"prod", "true", "api"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasPositional(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("one", "deploy", "--env", "$2", "$1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "one", "api", "stage"}) != 0 || !reflect.DeepEqual(called, []string{"stage", "false", "api"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasPositionalRest(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("one", "deploy", "--env", "$2", "$1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "one", "api", "stage", "web"}) != 0 || !reflect.DeepEqual(called, []string{"stage", "false", "api", "web"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasNotUsed(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("deploy-prod", "deploy", "--env", "prod", "--confirm"); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "deploy", "api"}) != 0 || !reflect.DeepEqual(called, []string{"dev", "false", "api"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasAll(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("all", "deploy", "--confirm", "$@", "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "all", "a", "b"}) != 0 || !reflect.DeepEqual(called, []string{"dev", "true", "a", "b", "db"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasMissingPositional(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("one", "deploy", "--env", "$2", "$1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := grp.ExecuteBest([]string{"tool", "one", "api"}); err == nil || !strings.Contains(err.Error(), "at least 2") || called != nil {
+		t.Fatal(err, called)
+	}
+}
+
+func TestLoadAliases(t *testing.T) {
+	var called []string
+
+	grp := new(Group)
+
+	if err := NewCommand("deploy").
+		AddConstant("tool", false).
+		AddConstant("deploy", false).
+		AddFlag("env", "environment", new(StringConverter), "dev").
+		AddParameterlessFlag("confirm", "", new(BooleanConverter), true, false).
+		AddVariadic("services", "", new(StringConverter)).
+		ToGroup(grp, func(env string, confirm bool, services ...string) {
+			called = append([]string{env, fmt.Sprint(confirm)}, services...)
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := grp.LoadAliases(strings.NewReader(`
+# production
+alias deploy-prod = deploy --env prod --confirm
+dp = deploy-prod
+one = deploy --env $2 $1
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"tool", "dp", "api"}) != 0 || !reflect.DeepEqual(called, []string{"prod", "true", "api"}) {
+		t.Fatal(called)
+	}
+}
+
+func TestAliasesList(t *testing.T) {
+	grp := new(Group)
+
+	err := grp.LoadAliases(strings.NewReader(`
+alias deploy-prod = deploy --env prod --confirm
+dp = deploy-prod
+one = deploy --env $2 $1
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a := grp.Aliases(); len(a) != 3 || a[0].String() != "deploy-prod = deploy --env prod --confirm" {
+		t.Fatal(a)
+	}
+}
+
+func TestAliasCycle(t *testing.T) {
+	grp := new(Group)
+
+	if err := grp.AddAlias("a", "b", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("b", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("c", "a"); err == nil || !strings.Contains(err.Error(), "c -> a -> b -> c") {
+		t.Fatal(err)
+	}
+}
+
+func TestAliasCycleAfterProgramName(t *testing.T) {
+	grp := new(Group)
+
+	if err := grp.AddAlias("x", "tool", "y"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("y", "tool", "--flag", "x"); err == nil || !strings.Contains(err.Error(), "y -> x -> y") {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadAliasesErrors(t *testing.T) {
+	grp := new(Group)
+
+	if err := grp.AddAlias("x", "tool", "y"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, def := range []string{"x = y", "z = z", "no equals", " = y", "e ="} {
+		if err := grp.LoadAliases(strings.NewReader(def)); err == nil {
+			t.Fatal(def)
+		}
+	}
+}
+
+func TestAliasesWriteTo(t *testing.T) {
+	grp := new(Group)
+
+	if err := NewCommand("deploy").AddConstant("tool", false).AddConstant("deploy", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.AddAlias("deploy-prod", "deploy", "--env", "prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := grp.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(buf.String(), "\n\naliases:\n\tdeploy-prod\t= deploy --env prod") {
+		t.Fatalf("%q", buf.String())
+	}
+}
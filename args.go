@@ -64,11 +64,11 @@ func (v *variable) Extract(args []string) ([]string, []interface{}, error) {
 
 func (v *variable) WriteTo(w io.Writer) (int64, error) {
 	if v.def != nil {
-		n, err := fmt.Fprintf(w, "\n\t%s\t%s (default: %v)", v.name, v.desc, v.def)
+		n, err := fmt.Fprintf(w, "\n\t%s\t%s (default: %v)", v.name, describe(v.desc, v.conv), v.def)
 		return int64(n), err
 	}
 
-	n, err := fmt.Fprintf(w, "\n\t%s\t%s", v.name, v.desc)
+	n, err := fmt.Fprintf(w, "\n\t%s\t%s", v.name, describe(v.desc, v.conv))
 
 	return int64(n), err
 }
@@ -108,10 +108,73 @@ func (v *variadic) Extract(args []string) ([]string, []interface{}, error) {
 }
 
 func (v *variadic) WriteTo(w io.Writer) (int64, error) {
-	n, err := fmt.Fprintf(w, "\n\t%s...\t%s", v.name, v.desc)
+	n, err := fmt.Fprintf(w, "\n\t%s...\t%s", v.name, describe(v.desc, v.conv))
 	return int64(n), err
 }
 
 func (v *variadic) String() string {
 	return fmt.Sprintf("[%s...]", v.name)
 }
+
+// describe appends the converter's constraints to
+// the argument's description
+func describe(desc string, conv Converter) string {
+	cd, ok := conv.(ConstraintDescriber)
+
+	if !ok {
+		return desc
+	}
+
+	c := cd.Constraints()
+
+	if c == "" {
+		return desc
+	}
+
+	if desc == "" {
+		return fmt.Sprintf("(%s)", c)
+	}
+
+	return fmt.Sprintf("%s (%s)", desc, c)
+}
+
+// paramNamer is implemented by arguments that know
+// the names of the parameters they extract
+type paramNamer interface {
+	paramNames() []string
+}
+
+func (v *variable) paramNames() []string {
+	return []string{v.name}
+}
+
+func (v *variadic) paramNames() []string {
+	return []string{v.name}
+}
+
+// paramNames returns the names of the n parameters extracted
+// by the argument, the last name is repeated if there are more
+// parameters than names
+func paramNames(arg Argument, n int) []string {
+	var names []string
+
+	if pn, ok := arg.(paramNamer); ok {
+		names = pn.paramNames()
+	}
+
+	if len(names) == 0 {
+		names = []string{arg.String()}
+	}
+
+	res := make([]string, n)
+
+	for i := range res {
+		if i < len(names) {
+			res[i] = names[i]
+		} else {
+			res[i] = names[len(names)-1]
+		}
+	}
+
+	return res
+}
@@ -1,10 +1,13 @@
 package funcv
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 	"regexp"
+	"strings"
 )
 
 var (
@@ -13,10 +16,16 @@ var (
 )
 
 type command struct {
-	args   []Argument
-	err    error
-	params []interface{}
-	desc   string
+	args       []Argument
+	checks     []check
+	registry   Registry
+	err        error
+	desc       string
+	structType reflect.Type
+	fields     []structField
+
+	interceptors interceptors
+	meta         metadata
 }
 
 func (c *command) AddArgument(arg Argument) Builder {
@@ -80,7 +89,6 @@ func (c *command) AddFlag(name, desc string, conv Converter, def interface{}) Bu
 
 	fb := &flagsBuilder{
 		converters: make(map[string]Converter),
-		values:     make(map[string]interface{}),
 		founddefs:  make(map[string]interface{}),
 		defaults:   make(map[string]interface{}),
 		command:    c}
@@ -95,7 +103,6 @@ func (c *command) AddParameterlessFlag(name, desc string, conv Converter, found,
 
 	fb := &flagsBuilder{
 		converters: make(map[string]Converter),
-		values:     make(map[string]interface{}),
 		founddefs:  make(map[string]interface{}),
 		defaults:   make(map[string]interface{}),
 		command:    c}
@@ -116,6 +123,57 @@ func (c *command) AddVariadic(name, desc string, conv Converter) Compiler {
 	return c.AddArgument(&variadic{name: name, desc: desc, conv: conv})
 }
 
+func (c *command) AddCheck(desc string, fn func(values map[string]interface{}) error) Compiler {
+	if c.err != nil {
+		return c
+	}
+
+	if fn == nil {
+		c.err = fmt.Errorf("funcv: check %s is nil", desc)
+		return c
+	}
+
+	c.checks = append(c.checks, check{desc: desc, fn: fn})
+	return c
+}
+
+func (c *command) Provide(values ...interface{}) Compiler {
+	if c.err != nil {
+		return c
+	}
+
+	if err := c.registry.Provide(values...); err != nil {
+		c.err = err
+	}
+
+	return c
+}
+
+func (c *command) Use(mw ...Middleware) Compiler {
+	if c.err != nil {
+		return c
+	}
+
+	for _, m := range mw {
+		if m == nil {
+			c.err = fmt.Errorf("funcv: middleware is nil")
+			return c
+		}
+	}
+
+	c.interceptors.middleware = append(c.interceptors.middleware, mw...)
+	return c
+}
+
+func (c *command) AddHooks(hooks Hooks) Compiler {
+	if c.err != nil {
+		return c
+	}
+
+	c.interceptors.hooks = append(c.interceptors.hooks, hooks)
+	return c
+}
+
 func (c *command) Compile() (Command, error) {
 	if c.err != nil {
 		return nil, c.err
@@ -125,7 +183,29 @@ func (c *command) Compile() (Command, error) {
 		return nil, ErrNoArguments
 	}
 
-	return c, nil
+	return c.snapshot(), nil
+}
+
+// snapshot returns a copy of the command that is not
+// affected by further building with the builder
+func (c *command) snapshot() *command {
+	cp := *c
+	cp.args = make([]Argument, len(c.args))
+
+	for i, arg := range c.args {
+		if fb, ok := arg.(*flagsBuilder); ok {
+			arg = fb.clone(&cp)
+		}
+
+		cp.args[i] = arg
+	}
+
+	cp.checks = append([]check(nil), c.checks...)
+	cp.fields = append([]structField(nil), c.fields...)
+	cp.registry = c.registry.clone()
+	cp.interceptors = c.interceptors.clone()
+
+	return &cp
 }
 
 func (c *command) MustCompile() Command {
@@ -138,6 +218,22 @@ func (c *command) MustCompile() Command {
 	return cmd
 }
 
+func (c *command) CompileFor(fn interface{}) (Command, error) {
+	cmd, err := c.Compile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	cc := cmd.(*command)
+
+	if err := cc.checkFunc(fn, &binder{cmd: cc, registries: []*Registry{&cc.registry}}); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
 func (c *command) ToGroup(grp *Group, fn interface{}) error {
 	cmd, err := c.Compile()
 
@@ -145,93 +241,114 @@ func (c *command) ToGroup(grp *Group, fn interface{}) error {
 		return err
 	}
 
-	grp.Add(cmd, fn)
-
-	return nil
+	return grp.AddChecked(cmd, fn)
 }
 
 func (c *command) Execute(args []string, fn interface{}) (int, error) {
-	n := 0
+	return c.ExecuteContext(context.Background(), args, fn)
+}
 
-	if c.err != nil {
-		return n, c.err
+func (c *command) ExecuteContext(ctx context.Context, args []string, fn interface{}) (int, error) {
+	e := executionOf(ctx)
+	res, err := e.parse(ctx, c, args)
+
+	if err != nil {
+		return res.N, err
 	}
 
-	c.params = nil
+	return res.N, res.execute(ctx, e, fn)
+}
 
-	var err error
-	var params []interface{}
+func (c *command) Parse(args []string) (*Result, error) {
+	res, err := c.parse(args)
+	res.err = err
 
-	for _, arg := range c.args {
-		l := len(args)
-		args, params, err = arg.Extract(args)
-		n += l - len(args)
+	return res, err
+}
 
-		if err != nil {
-			return n, err
-		}
+// parse extracts the command's arguments into a result
+func (c *command) parse(args []string) (*Result, error) {
+	res := &Result{
+		Values:    make(map[string]interface{}),
+		Set:       make(map[string]bool),
+		Remaining: args,
+		cmd:       c}
 
-		c.params = append(c.params, params...)
+	if c.err != nil {
+		return res, c.err
 	}
 
-	if len(args) > 0 {
-		return n, fmt.Errorf("funcv: %v (%w)", args, ErrUnknownArgs)
-	}
+	var err error
+	var extracted []interface{}
 
-	if fn == nil {
-		return n, nil
-	}
+	for _, arg := range c.args {
+		l := len(args)
 
-	vfn := reflect.ValueOf(fn)
+		if fb, ok := arg.(*flagsBuilder); ok {
+			var set map[string]bool
 
-	if vfn.Type().Kind() != reflect.Func {
-		return n, fmt.Errorf("funcv: invalid function [%v]", vfn.Type().Kind())
-	}
+			args, extracted, set, err = fb.extract(args)
 
-	if vfn.Type().NumIn() != len(c.params) {
+			for name, found := range set {
+				res.Set[name] = found
+			}
+		} else {
+			args, extracted, err = arg.Extract(args)
 
-		if !vfn.Type().IsVariadic() {
-			return n, fmt.Errorf("funcv: invalid function params count [count: %d, input: %d]", vfn.Type().NumIn(), len(c.params))
+			if v, ok := arg.(*variable); ok && v.def != nil {
+				res.Set[v.name] = l > len(args)
+			}
 		}
 
-		if len(c.params) < vfn.Type().NumIn()-1 {
-			return n, fmt.Errorf("funcv: invalid variadic function params count [count: %d..inf, input=%d]", vfn.Type().NumIn()-1, len(c.params))
+		res.N += l - len(args)
+		res.Remaining = args
+
+		if err != nil {
+			res.failed = arg
+			return res, err
 		}
-	}
 
-	var in []reflect.Value
+		res.params = append(res.params, extracted...)
+		res.names = append(res.names, paramNames(arg, len(extracted))...)
+	}
 
-	i := 0
+	res.Values = valuesByName(res.names, res.params)
 
-	for _, param := range c.params {
-		v := reflect.ValueOf(param)
-		t := vfn.Type().In(i)
+	if len(args) > 0 {
+		return res, fmt.Errorf("funcv: %v (%w)", args, ErrUnknownArgs)
+	}
 
-		if i+1 == vfn.Type().NumIn() && vfn.Type().IsVariadic() {
-			t = t.Elem()
-		} else {
-			i++
-		}
+	if err := c.check(res.names, res.params); err != nil {
+		return res, err
+	}
 
-		if !v.Type().ConvertibleTo(t) {
-			return n, fmt.Errorf("funcv: can't convert param %v to %v", v.Type(), t)
-		}
+	return res, nil
+}
 
-		in = append(in, v.Convert(t))
+// check runs all the command's checks and returns
+// their failures together in a *ValidationError
+func (c *command) check(names []string, params []interface{}) error {
+	if len(c.checks) == 0 {
+		return nil
 	}
 
-	ret := vfn.Call(in)
+	values := valuesByName(names, params)
 
-	if len(ret) > 0 {
+	var errs []error
+	var failed []string
 
-		if r := ret[len(ret)-1].Interface(); r != nil {
-			if err, ok := r.(error); ok && err != nil {
-				return n, err
-			}
+	for _, chk := range c.checks {
+		if err := chk.fn(values); err != nil {
+			errs = append(errs, err)
+			failed = append(failed, chk.desc)
 		}
 	}
 
-	return n, nil
+	if len(errs) > 0 {
+		return &ValidationError{Value: values, Errs: errs, Checks: failed}
+	}
+
+	return nil
 }
 
 func (c *command) WriteTo(w io.Writer) (int64, error) {
@@ -241,8 +358,8 @@ func (c *command) WriteTo(w io.Writer) (int64, error) {
 		return written, nil
 	}
 
-	if c.desc != "" {
-		if n, err := fmt.Fprintf(w, "%s:\t", c.desc); err == nil {
+	if c.desc != "" || c.meta.notes() != "" {
+		if n, err := fmt.Fprintf(w, "%s%s:\t", c.desc, c.meta.notes()); err == nil {
 			written += int64(n)
 		} else {
 			return written + int64(n), err
@@ -253,42 +370,148 @@ func (c *command) WriteTo(w io.Writer) (int64, error) {
 		return written + int64(n), err
 	}
 
-	for i, arg := range c.args {
+	var usage []string
 
-		if i == 0 {
-			if n, err := fmt.Fprint(w, "> "); err == nil {
-				written += int64(n)
-			} else {
-				return written + int64(n), err
-			}
+	for _, arg := range c.args {
+		if s := arg.String(); s != "" {
+			usage = append(usage, s)
 		}
+	}
 
-		if n, err := fmt.Fprintf(w, "%s", arg.String()); err == nil {
-			written += int64(n)
+	if n, err := fmt.Fprintf(w, "> %s\n", strings.Join(usage, " ")); err == nil {
+		written += int64(n)
+	} else {
+		return written + int64(n), err
+	}
+
+	for _, arg := range c.args {
+		if n, err := arg.WriteTo(w); err == nil {
+			written += n
 		} else {
-			return written + int64(n), err
+			return written + n, err
 		}
+	}
 
-		if i+1 < len(c.args) {
-			if n, err := fmt.Fprint(w, " "); err == nil {
-				written += int64(n)
-			} else {
-				return written + int64(n), err
-			}
-		} else if n, err := fmt.Fprint(w, "\n"); err == nil {
+	for _, chk := range c.checks {
+		if n, err := fmt.Fprintf(w, "\n\t\t%s", chk.desc); err == nil {
 			written += int64(n)
 		} else {
 			return written + int64(n), err
 		}
 	}
 
-	for _, arg := range c.args {
-		if n, err := arg.WriteTo(w); err == nil {
-			written += n
-		} else {
-			return written + n, err
+	return written, nil
+}
+
+// convertParam converts an extracted parameter to the action
+// function's parameter type, slices are converted item by item,
+// numeric conversions that lose information fail
+func convertParam(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if (isInt(v.Kind()) || isUint(v.Kind())) && t.Kind() == reflect.String {
+		return v, fmt.Errorf("can't convert %v to %v", v.Type(), t)
+	}
+
+	if v.Type().ConvertibleTo(t) {
+		if err := checkLossless(v, t); err != nil {
+			return v, err
 		}
+
+		return v.Convert(t), nil
 	}
 
-	return written, nil
+	if v.Kind() == reflect.Slice && t.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(t, v.Len(), v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			item, err := convertParam(v.Index(i), t.Elem())
+
+			if err != nil {
+				return s, err
+			}
+
+			s.Index(i).Set(item)
+		}
+
+		return s, nil
+	}
+
+	return v, fmt.Errorf("can't convert %v to %v", v.Type(), t)
+}
+
+// checkLossless returns an error if converting the numeric value
+// v to t overflows, loses the sign, truncates a fraction or rounds
+// an integer
+func checkLossless(v reflect.Value, t reflect.Type) error {
+	dst := reflect.Zero(t)
+
+	switch {
+	case isInt(v.Kind()) && isInt(t.Kind()):
+		if dst.OverflowInt(v.Int()) {
+			return fmt.Errorf("%v overflows %v (%w)", v, t, ErrLossyConversion)
+		}
+	case isInt(v.Kind()) && isUint(t.Kind()):
+		if v.Int() < 0 {
+			return fmt.Errorf("negative %v can't be %v (%w)", v, t, ErrLossyConversion)
+		}
+
+		if dst.OverflowUint(uint64(v.Int())) {
+			return fmt.Errorf("%v overflows %v (%w)", v, t, ErrLossyConversion)
+		}
+	case isUint(v.Kind()) && isUint(t.Kind()):
+		if dst.OverflowUint(v.Uint()) {
+			return fmt.Errorf("%v overflows %v (%w)", v, t, ErrLossyConversion)
+		}
+	case isUint(v.Kind()) && isInt(t.Kind()):
+		if v.Uint() > math.MaxInt64 || dst.OverflowInt(int64(v.Uint())) {
+			return fmt.Errorf("%v overflows %v (%w)", v, t, ErrLossyConversion)
+		}
+	case (isInt(v.Kind()) || isUint(v.Kind())) && isFloat(t.Kind()):
+		f := v.Convert(t).Float()
+
+		if isInt(v.Kind()) && (f >= math.MaxInt64 || int64(f) != v.Int()) ||
+			isUint(v.Kind()) && (f >= math.MaxUint64 || uint64(f) != v.Uint()) {
+			return fmt.Errorf("%v can't be %v without rounding (%w)", v, t, ErrLossyConversion)
+		}
+	case isFloat(v.Kind()) && isFloat(t.Kind()):
+		f := v.Float()
+
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil
+		}
+
+		// rounding a decimal fraction is expected
+		if dst.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %v (%w)", v, t, ErrLossyConversion)
+		}
+	case isFloat(v.Kind()) && (isInt(t.Kind()) || isUint(t.Kind())):
+		f := v.Float()
+
+		if math.IsInf(f, 0) || math.IsNaN(f) || f != math.Trunc(f) {
+			return fmt.Errorf("%v can't be %v without truncation (%w)", v, t, ErrLossyConversion)
+		}
+
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return fmt.Errorf("%v overflows %v (%w)", v, t, ErrLossyConversion)
+		}
+
+		return checkLossless(reflect.ValueOf(int64(f)), t)
+	}
+
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
 }
@@ -2,18 +2,31 @@ package funcv
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // StringConverter is used to convert string arguments to strings
 type StringConverter struct{}
 
 // Convert returns the given arg as-is
-func (*StringConverter) Convert(arg string) (interface{}, error) {
+func (c *StringConverter) Convert(arg string) (interface{}, error) {
+	s, err := c.ConvertTo(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ConvertTo returns the given arg as-is
+func (*StringConverter) ConvertTo(arg string) (string, error) {
 	if arg == "" {
-		return nil, ErrInvalidValue
+		return "", ErrInvalidValue
 	}
 
 	return arg, nil
@@ -27,25 +40,78 @@ func (*StringConverter) IsSupported(v interface{}) bool {
 // IntegerConverter is used to convert string represented integer
 // arguments to integers, the default base is decimal
 type IntegerConverter struct {
-	Base int // input base (0 or less defaults to decimal)
+	Base         int    // input base (0 or less defaults to decimal)
+	BitSize      int    // result bit size (0 or less defaults to 64)
+	Unsigned     bool   // reject negative values
+	Min          *int64 // lower bound (nil for no bound)
+	Max          *int64 // upper bound (nil for no bound)
+	ExclusiveMin bool   // exclude Min from the valid range
+	ExclusiveMax bool   // exclude Max from the valid range
+	MultipleOf   int64  // the value must be a multiple of (0 for any value)
 }
 
 // Convert the given argument to integer
 func (c *IntegerConverter) Convert(arg string) (interface{}, error) {
+	i, err := c.ConvertTo(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// ConvertTo converts the given argument to int64
+func (c *IntegerConverter) ConvertTo(arg string) (int64, error) {
 	if arg == "" {
-		return nil, ErrInvalidValue
+		return 0, ErrInvalidValue
 	}
 
-	base := 10
+	if c == nil {
+		c = new(IntegerConverter)
+	}
 
-	if c != nil && c.Base > 0 {
+	base, bits := 10, 64
+
+	if c.Base > 0 {
 		base = c.Base
 	}
 
-	i, err := strconv.ParseInt(arg, base, 64)
+	if c.BitSize > 0 {
+		bits = c.BitSize
+	}
+
+	var i int64
+	var err error
+
+	if c.Unsigned {
+		var u uint64
+
+		if u, err = strconv.ParseUint(arg, base, bits); err == nil {
+			if u > math.MaxInt64 {
+				err = strconv.ErrRange
+			}
+
+			i = int64(u)
+		}
+	} else {
+		i, err = strconv.ParseInt(arg, base, bits)
+	}
 
 	if err != nil {
-		return nil, fmt.Errorf("funcv: failed to parse int var %v (%w)", arg, err)
+		return 0, fmt.Errorf("funcv: failed to parse int var %v (%w)", arg, err)
+	}
+
+	if c.Min != nil && (i < *c.Min || c.ExclusiveMin && i == *c.Min) {
+		return 0, fmt.Errorf("funcv: int var %v is out of range %s (%w)", arg, c.bounds(), ErrInvalidValue)
+	}
+
+	if c.Max != nil && (i > *c.Max || c.ExclusiveMax && i == *c.Max) {
+		return 0, fmt.Errorf("funcv: int var %v is out of range %s (%w)", arg, c.bounds(), ErrInvalidValue)
+	}
+
+	if c.MultipleOf != 0 && i%c.MultipleOf != 0 {
+		return 0, fmt.Errorf("funcv: int var %v is not a multiple of %d (%w)", arg, c.MultipleOf, ErrInvalidValue)
 	}
 
 	return i, nil
@@ -57,6 +123,48 @@ func (*IntegerConverter) IsSupported(v interface{}) bool {
 	return reflect.TypeOf(v).ConvertibleTo(reflect.TypeOf(int64(0)))
 }
 
+func (c *IntegerConverter) bounds() string {
+	var min, max string
+
+	if c.Min != nil {
+		min = strconv.FormatInt(*c.Min, 10)
+	}
+
+	if c.Max != nil {
+		max = strconv.FormatInt(*c.Max, 10)
+	}
+
+	return formatBounds(min, max, c.ExclusiveMin, c.ExclusiveMax)
+}
+
+// Constraints returns a description of the converter's
+// constraints (ex: "1..65535, multiple of 5")
+func (c *IntegerConverter) Constraints() string {
+	if c == nil {
+		return ""
+	}
+
+	var parts []string
+
+	if b := c.bounds(); b != "" {
+		parts = append(parts, b)
+	} else if c.Unsigned {
+		parts = append(parts, "unsigned")
+	}
+
+	if c.MultipleOf != 0 {
+		parts = append(parts, fmt.Sprintf("multiple of %d", c.MultipleOf))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Int64 returns a pointer to the given value, it can be used
+// for setting the IntegerConverter bounds
+func Int64(v int64) *int64 {
+	return &v
+}
+
 // BooleanConverter is used to convert string represented integer
 // arguments to integers, "true" is converted to true, "false"
 // is converted to false, it uses sensitive compare as default
@@ -66,6 +174,17 @@ type BooleanConverter struct {
 
 // Convert the given argument to boolean
 func (c *BooleanConverter) Convert(arg string) (interface{}, error) {
+	b, err := c.ConvertTo(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// ConvertTo converts the given argument to bool
+func (c *BooleanConverter) ConvertTo(arg string) (bool, error) {
 	if arg == "" {
 		return true, nil
 	}
@@ -94,7 +213,7 @@ func (c *BooleanConverter) Convert(arg string) (interface{}, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("funcv: cannot convert %s to boolean", arg)
+	return false, fmt.Errorf("funcv: cannot convert %s to boolean", arg)
 }
 
 // IsSupported returns true if the given value is a boolean
@@ -105,21 +224,67 @@ func (*BooleanConverter) IsSupported(v interface{}) bool {
 
 // FloatConverter is used to convert string represented float
 // arguments to float
-type FloatConverter struct{}
+type FloatConverter struct {
+	BitSize      int      // result bit size (32 or 64, 0 or less defaults to 64)
+	Min          *float64 // lower bound (nil for no bound)
+	Max          *float64 // upper bound (nil for no bound)
+	ExclusiveMin bool     // exclude Min from the valid range
+	ExclusiveMax bool     // exclude Max from the valid range
+	Step         float64  // the value must be a multiple of (0 for any value)
+}
 
 // Convert the given argument to float
-func (*FloatConverter) Convert(arg string) (interface{}, error) {
+func (c *FloatConverter) Convert(arg string) (interface{}, error) {
+	f, err := c.ConvertTo(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ConvertTo converts the given argument to float64
+func (c *FloatConverter) ConvertTo(arg string) (float64, error) {
 	if arg == "" {
-		return nil, ErrInvalidValue
+		return 0, ErrInvalidValue
+	}
+
+	if c == nil {
+		c = new(FloatConverter)
 	}
 
-	i, err := strconv.ParseFloat(arg, 64)
+	bits := 64
+
+	if c.BitSize > 0 {
+		bits = c.BitSize
+	}
+
+	f, err := strconv.ParseFloat(arg, bits)
 
 	if err != nil {
-		return nil, fmt.Errorf("funcv: failed to parse float var %v (%w)", arg, err)
+		return 0, fmt.Errorf("funcv: failed to parse float var %v (%w)", arg, err)
 	}
 
-	return i, nil
+	if math.IsNaN(f) && (c.Min != nil || c.Max != nil || c.Step != 0) {
+		return 0, fmt.Errorf("funcv: float var %v is out of range %s (%w)", arg, c.bounds(), ErrInvalidValue)
+	}
+
+	if c.Min != nil && (f < *c.Min || c.ExclusiveMin && f == *c.Min) {
+		return 0, fmt.Errorf("funcv: float var %v is out of range %s (%w)", arg, c.bounds(), ErrInvalidValue)
+	}
+
+	if c.Max != nil && (f > *c.Max || c.ExclusiveMax && f == *c.Max) {
+		return 0, fmt.Errorf("funcv: float var %v is out of range %s (%w)", arg, c.bounds(), ErrInvalidValue)
+	}
+
+	if c.Step != 0 {
+		if q := f / c.Step; math.Abs(q-math.Round(q)) > 1e-9 {
+			return 0, fmt.Errorf("funcv: float var %v is not a multiple of %v (%w)", arg, c.Step, ErrInvalidValue)
+		}
+	}
+
+	return f, nil
 }
 
 // IsSupported returns true if the given value is a float
@@ -127,3 +292,389 @@ func (*FloatConverter) Convert(arg string) (interface{}, error) {
 func (*FloatConverter) IsSupported(v interface{}) bool {
 	return reflect.TypeOf(v).ConvertibleTo(reflect.TypeOf(float64(0)))
 }
+
+func (c *FloatConverter) bounds() string {
+	var min, max string
+
+	if c.Min != nil {
+		min = strconv.FormatFloat(*c.Min, 'g', -1, 64)
+	}
+
+	if c.Max != nil {
+		max = strconv.FormatFloat(*c.Max, 'g', -1, 64)
+	}
+
+	return formatBounds(min, max, c.ExclusiveMin, c.ExclusiveMax)
+}
+
+// Constraints returns a description of the converter's
+// constraints (ex: "(0..1]")
+func (c *FloatConverter) Constraints() string {
+	if c == nil {
+		return ""
+	}
+
+	var parts []string
+
+	if b := c.bounds(); b != "" {
+		parts = append(parts, b)
+	}
+
+	if c.Step != 0 {
+		parts = append(parts, fmt.Sprintf("multiple of %v", c.Step))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Float64 returns a pointer to the given value, it can be used
+// for setting the FloatConverter bounds
+func Float64(v float64) *float64 {
+	return &v
+}
+
+// formatBounds formats a range, inclusive ranges are written
+// as min..max and ranges with an exclusive end use brackets
+func formatBounds(min, max string, exclusiveMin, exclusiveMax bool) string {
+	if min == "" && max == "" {
+		return ""
+	}
+
+	exclusiveMin = exclusiveMin && min != ""
+	exclusiveMax = exclusiveMax && max != ""
+
+	if !exclusiveMin && !exclusiveMax {
+		return min + ".." + max
+	}
+
+	left, right := "[", "]"
+
+	if exclusiveMin || min == "" {
+		left = "("
+	}
+
+	if exclusiveMax || max == "" {
+		right = ")"
+	}
+
+	return left + min + ".." + max + right
+}
+
+// Range of integers, a nil end is unbounded
+type Range struct {
+	From *int64
+	To   *int64
+}
+
+// Contains returns true if the given value is within the range
+func (r Range) Contains(i int64) bool {
+	return (r.From == nil || i >= *r.From) && (r.To == nil || i <= *r.To)
+}
+
+func (r Range) String() string {
+	var from, to string
+
+	if r.From != nil {
+		from = strconv.FormatInt(*r.From, 10)
+	}
+
+	if r.To != nil {
+		to = strconv.FormatInt(*r.To, 10)
+	}
+
+	return from + ":" + to
+}
+
+// RangeConverter is used to convert range expressions to a Range,
+// supported expressions are "from-to", "from:to", "from:", ":to"
+// and a single value, the ends are converted using Ends
+type RangeConverter struct {
+	Ends *IntegerConverter // the ends converter (nil defaults to decimal)
+}
+
+// Convert the given argument to a Range
+func (c *RangeConverter) Convert(arg string) (interface{}, error) {
+	r, err := c.ConvertTo(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ConvertTo converts the given argument to a Range
+func (c *RangeConverter) ConvertTo(arg string) (Range, error) {
+	var r Range
+
+	if arg == "" {
+		return r, ErrInvalidValue
+	}
+
+	var ends *IntegerConverter
+
+	if c != nil {
+		ends = c.Ends
+	}
+
+	from, to, found := strings.Cut(arg, ":")
+
+	if !found {
+		// a leading dash is a sign
+		if i := strings.Index(arg[1:], "-"); i >= 0 {
+			from, to, found = arg[:i+1], arg[i+2:], true
+
+			if from == "" || to == "" {
+				return r, fmt.Errorf("funcv: invalid range %v (%w)", arg, ErrInvalidValue)
+			}
+		}
+	}
+
+	if from != "" {
+		i, err := ends.ConvertTo(from)
+
+		if err != nil {
+			return r, err
+		}
+
+		r.From = &i
+	}
+
+	if !found {
+		r.To = r.From
+		return r, nil
+	}
+
+	if to != "" {
+		i, err := ends.ConvertTo(to)
+
+		if err != nil {
+			return r, err
+		}
+
+		r.To = &i
+	}
+
+	if r.From == nil && r.To == nil {
+		return r, fmt.Errorf("funcv: invalid range %v (%w)", arg, ErrInvalidValue)
+	}
+
+	if r.From != nil && r.To != nil && *r.From > *r.To {
+		return r, fmt.Errorf("funcv: invalid range %v, %d > %d (%w)", arg, *r.From, *r.To, ErrInvalidValue)
+	}
+
+	return r, nil
+}
+
+// IsSupported returns true if the given value is a Range
+func (*RangeConverter) IsSupported(v interface{}) bool {
+	_, ok := v.(Range)
+	return ok
+}
+
+// Constraints returns a description of the range ends constraints
+func (c *RangeConverter) Constraints() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Ends.Constraints()
+}
+
+// ListConverter is used to convert a separated list argument
+// (ex: "1,2,3") to a slice, each item is converted using the
+// element converter, items can be quoted ('...' or "...") or
+// escaped (\,) to include the separator
+type ListConverter struct {
+	Elem      Converter // items converter (nil defaults to strings)
+	Separator string    // items separator (empty defaults to ",")
+	Min       int       // minimum number of items (0 or less for no minimum)
+	Max       int       // maximum number of items (0 or less for no maximum)
+	Unique    bool      // reject duplicate items
+}
+
+func (c *ListConverter) elem() Converter {
+	if c == nil || c.Elem == nil {
+		return new(StringConverter)
+	}
+
+	return c.Elem
+}
+
+func (c *ListConverter) separator() string {
+	if c == nil || c.Separator == "" {
+		return ","
+	}
+
+	return c.Separator
+}
+
+// Convert the given argument to a slice of the element
+// converter's type ([]interface{} if the type is unknown)
+func (c *ListConverter) Convert(arg string) (interface{}, error) {
+	if arg == "" {
+		return nil, ErrInvalidValue
+	}
+
+	items, err := splitList(arg, c.separator())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil && c.Min > 0 && len(items) < c.Min {
+		return nil, fmt.Errorf("funcv: list %v has less than %d items (%w)", arg, c.Min, ErrInvalidValue)
+	}
+
+	if c != nil && c.Max > 0 && len(items) > c.Max {
+		return nil, fmt.Errorf("funcv: list %v has more than %d items (%w)", arg, c.Max, ErrInvalidValue)
+	}
+
+	conv := c.elem()
+	t := outputType(conv)
+
+	if t == nil {
+		t = reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+
+	list := reflect.MakeSlice(reflect.SliceOf(t), 0, len(items))
+	seen := make(map[interface{}]bool)
+
+	for _, item := range items {
+		p, err := conv.Convert(item)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if c != nil && c.Unique {
+			var key interface{} = fmt.Sprint(p)
+
+			if v := reflect.ValueOf(p); v.IsValid() && v.Comparable() {
+				key = p
+			}
+
+			if seen[key] {
+				return nil, fmt.Errorf("funcv: duplicate item %v in list %v (%w)", p, arg, ErrInvalidValue)
+			}
+
+			seen[key] = true
+		}
+
+		v := reflect.ValueOf(p)
+
+		if !v.IsValid() {
+			v = reflect.Zero(t)
+		} else if !v.Type().AssignableTo(t) {
+			if !v.Type().ConvertibleTo(t) {
+				return nil, fmt.Errorf("funcv: can't convert list item %v to %v", p, t)
+			}
+
+			v = v.Convert(t)
+		}
+
+		list = reflect.Append(list, v)
+	}
+
+	return list.Interface(), nil
+}
+
+// IsSupported returns true if the given value is a slice
+// and all of its items are supported by the element converter
+func (c *ListConverter) IsSupported(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Slice {
+		return false
+	}
+
+	conv := c.elem()
+
+	for i := 0; i < rv.Len(); i++ {
+		if !conv.IsSupported(rv.Index(i).Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Constraints returns a description of the list's
+// constraints (ex: "1..3 items, unique")
+func (c *ListConverter) Constraints() string {
+	if c == nil {
+		return ""
+	}
+
+	var parts []string
+	var min, max string
+
+	if c.Min > 0 {
+		min = strconv.Itoa(c.Min)
+	}
+
+	if c.Max > 0 {
+		max = strconv.Itoa(c.Max)
+	}
+
+	if b := formatBounds(min, max, false, false); b != "" {
+		parts = append(parts, b+" items")
+	}
+
+	if c.Unique {
+		parts = append(parts, "unique")
+	}
+
+	if cd, ok := c.Elem.(ConstraintDescriber); ok {
+		if ec := cd.Constraints(); ec != "" {
+			parts = append(parts, "items "+ec)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func splitList(arg, sep string) ([]string, error) {
+	var items []string
+	var sb strings.Builder
+	var quote rune
+
+	for i := 0; i < len(arg); {
+		r, size := utf8.DecodeRuneInString(arg[i:])
+
+		switch {
+		case r == '\\':
+			if i+size >= len(arg) {
+				return nil, fmt.Errorf("funcv: dangling escape in %v (%w)", arg, ErrInvalidValue)
+			}
+
+			next, nsize := utf8.DecodeRuneInString(arg[i+size:])
+			sb.WriteRune(next)
+			i += size + nsize
+			continue
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case strings.HasPrefix(arg[i:], sep):
+			items = append(items, sb.String())
+			sb.Reset()
+			i += len(sep)
+			continue
+		default:
+			sb.WriteRune(r)
+		}
+
+		i += size
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("funcv: unterminated quote in %v (%w)", arg, ErrInvalidValue)
+	}
+
+	return append(items, sb.String()), nil
+}
@@ -0,0 +1,208 @@
+package funcv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestListFlag(t *testing.T) {
+	c := NewCommand("").AddFlag("ids", "", &ListConverter{Elem: new(IntegerConverter)}, []int64{}).MustCompile()
+
+	var v []int
+
+	_, err := c.Execute([]string{"--ids", "1,2,3"}, func(ids []int) {
+		v = ids
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(v) != 3 || v[0] != 1 || v[1] != 2 || v[2] != 3 {
+		t.Fatal("wrong value", v)
+	}
+}
+
+func TestListSeparator(t *testing.T) {
+	conv := &ListConverter{Separator: ";"}
+
+	v, err := conv.Convert(`a;"b;c";d\;e`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, ok := v.([]string)
+
+	if !ok || len(l) != 3 || l[0] != "a" || l[1] != "b;c" || l[2] != "d;e" {
+		t.Fatal("wrong value", v)
+	}
+}
+
+func TestListBounds(t *testing.T) {
+	conv := &ListConverter{Elem: new(IntegerConverter), Min: 2, Max: 3}
+
+	for _, arg := range []string{"1", "1,2,3,4"} {
+		if _, err := conv.Convert(arg); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(arg, err)
+		}
+	}
+
+	if _, err := conv.Convert("1,2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListUnique(t *testing.T) {
+	conv := &ListConverter{Elem: new(IntegerConverter), Unique: true}
+
+	if _, err := conv.Convert("1,2,01"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+
+	if _, err := conv.Convert("1,2,3"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListInvalid(t *testing.T) {
+	conv := &ListConverter{Elem: new(IntegerConverter)}
+
+	for _, arg := range []string{"", "1,x", `1,"2`, `1\`} {
+		if _, err := conv.Convert(arg); err == nil {
+			t.Fatal(arg)
+		}
+	}
+
+	if !conv.IsSupported([]int{1, 2}) || conv.IsSupported([]string{"x"}) || conv.IsSupported(1) {
+		t.FailNow()
+	}
+}
+
+func TestIntegerBounds(t *testing.T) {
+	conv := &IntegerConverter{Min: Int64(1), Max: Int64(65535)}
+
+	for _, arg := range []string{"0", "65536", "99999"} {
+		if _, err := conv.Convert(arg); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(arg, err)
+		}
+	}
+
+	for _, arg := range []string{"1", "80", "65535"} {
+		if _, err := conv.Convert(arg); err != nil {
+			t.Fatal(arg, err)
+		}
+	}
+
+	if c := conv.Constraints(); c != "1..65535" {
+		t.Fatal("wrong constraints", c)
+	}
+}
+
+func TestIntegerExclusiveBounds(t *testing.T) {
+	conv := &IntegerConverter{Min: Int64(0), ExclusiveMin: true, MultipleOf: 5}
+
+	for _, arg := range []string{"0", "-5", "7"} {
+		if _, err := conv.Convert(arg); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(arg, err)
+		}
+	}
+
+	if _, err := conv.Convert("10"); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := conv.Constraints(); c != "(0..), multiple of 5" {
+		t.Fatal("wrong constraints", c)
+	}
+}
+
+func TestIntegerBitSize(t *testing.T) {
+	conv := &IntegerConverter{BitSize: 8, Unsigned: true}
+
+	for _, arg := range []string{"256", "-1"} {
+		if _, err := conv.Convert(arg); err == nil {
+			t.Fatal(arg)
+		}
+	}
+
+	if v, err := conv.Convert("255"); err != nil || v != int64(255) {
+		t.Fatal(v, err)
+	}
+}
+
+func TestFloatBounds(t *testing.T) {
+	conv := &FloatConverter{Min: Float64(0), Max: Float64(1), ExclusiveMin: true}
+
+	for _, arg := range []string{"0", "-3", "1.5", "NaN", "Inf", "-Inf"} {
+		if _, err := conv.Convert(arg); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(arg, err)
+		}
+	}
+
+	if _, err := conv.Convert("0.25"); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := conv.Constraints(); c != "(0..1]" {
+		t.Fatal("wrong constraints", c)
+	}
+	if _, err := (&FloatConverter{Step: 0.5}).Convert("NaN"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+
+	if _, err := new(FloatConverter).Convert("NaN"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRangeConverter(t *testing.T) {
+	conv := new(RangeConverter)
+
+	tests := []struct {
+		arg      string
+		from, to string
+	}{
+		{"1-10", "1", "10"},
+		{"-5--1", "-5", "-1"},
+		{"5:", "5", ""},
+		{":7", "", "7"},
+		{"3", "3", "3"},
+	}
+
+	for _, test := range tests {
+		r, err := conv.ConvertTo(test.arg)
+
+		if err != nil {
+			t.Fatal(test.arg, err)
+		}
+
+		if s := r.String(); s != test.from+":"+test.to {
+			t.Fatal(test.arg, s)
+		}
+	}
+
+	for _, arg := range []string{"", ":", "10-1", "5-", "a-b"} {
+		if _, err := conv.Convert(arg); err == nil {
+			t.Fatal(arg)
+		}
+	}
+}
+
+func TestConstraintsUsage(t *testing.T) {
+	c := NewCommand("").
+		AddFlag("p", "port", &IntegerConverter{Min: Int64(1), Max: Int64(65535)}, 80).
+		AddVariable("ratio", "", &FloatConverter{Max: Float64(1)}).
+		MustCompile()
+
+	var sb strings.Builder
+
+	if _, err := c.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(sb.String(), "port (1..65535) (default: 80)") || !strings.Contains(sb.String(), "(..1)") {
+		t.Fatal(sb.String())
+	}
+}
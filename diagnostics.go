@@ -0,0 +1,322 @@
+package funcv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Reason of a command not matching the arguments
+type Reason int
+
+const (
+	// ReasonUnknownConstant means a token is not the expected constant
+	ReasonUnknownConstant Reason = iota
+	// ReasonInvalidValue means the converter failed to convert a token
+	ReasonInvalidValue
+	// ReasonMissingArgument means the arguments ended early
+	ReasonMissingArgument
+	// ReasonExtraArguments means tokens are left after the last argument
+	ReasonExtraArguments
+	// ReasonFailedCheck means a check of the command failed
+	ReasonFailedCheck
+	// ReasonRejected means the command was rejected (ex: by a hook)
+	ReasonRejected
+	// ReasonExperimental means the command or one of its flags
+	// is experimental and its feature isn't enabled
+	ReasonExperimental
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonUnknownConstant:
+		return "unknown constant"
+	case ReasonInvalidValue:
+		return "invalid value"
+	case ReasonMissingArgument:
+		return "missing argument"
+	case ReasonExtraArguments:
+		return "extra arguments"
+	case ReasonFailedCheck:
+		return "failed check"
+	case ReasonExperimental:
+		return "experimental"
+	}
+
+	return "rejected"
+}
+
+// Mismatch explains why the arguments don't match a command
+type Mismatch struct {
+	Index    int     // the command's index in the group
+	Command  Command // the command
+	Matched  int     // the number of matched tokens
+	Pos      int     // the offending token's position in the arguments (-1 if none)
+	Token    string  // the offending token (empty if missing)
+	Expected string  // the expected argument
+	Reason   Reason  // the reason of the mismatch
+	Err      error   // the parsing error
+}
+
+func (m *Mismatch) String() string {
+	switch m.Reason {
+	case ReasonUnknownConstant:
+		return fmt.Sprintf("unknown command %q, expected %q", m.Token, m.Expected)
+	case ReasonInvalidValue:
+		return fmt.Sprintf("invalid value %q for %s (%v)", m.Token, m.Expected, m.Err)
+	case ReasonMissingArgument:
+		return fmt.Sprintf("missing %s", m.Expected)
+	case ReasonExtraArguments:
+		return fmt.Sprintf("unexpected %q", m.Token)
+	case ReasonExperimental:
+		return strings.TrimPrefix(m.Err.Error(), "funcv: ")
+	}
+
+	return fmt.Sprintf("%v (%v)", m.Reason, m.Err)
+}
+
+// mismatch returns the mismatch of the arguments with the command
+// of the given index from the result and the error of parsing them
+func mismatch(i int, cmd Command, args []string, res *Result, err error) Mismatch {
+	m := Mismatch{Index: i, Command: cmd, Matched: res.N, Pos: -1, Reason: ReasonRejected, Err: err}
+
+	var ve *ValidationError
+
+	switch {
+	case errors.Is(err, ErrExperimental):
+		m.Reason = ReasonExperimental
+		return m
+	case errors.Is(err, ErrUnknownArgs):
+		m.Reason = ReasonExtraArguments
+	case errors.As(err, &ve):
+		m.Reason = ReasonFailedCheck
+		return m
+	case res.failed == nil:
+		return m
+	case res.N >= len(args):
+		m.Reason = ReasonMissingArgument
+	default:
+		if _, ok := res.failed.(*constant); ok {
+			m.Reason = ReasonUnknownConstant
+		} else {
+			m.Reason = ReasonInvalidValue
+		}
+	}
+
+	if res.N < len(args) {
+		m.Pos, m.Token = res.N, args[res.N]
+	} else {
+		m.Pos = len(args)
+	}
+
+	if _, ok := res.failed.(*flagsBuilder); ok && res.N > 0 {
+		m.Expected = args[res.N-1]
+	} else if res.failed != nil {
+		m.Expected = res.failed.String()
+	}
+
+	return m
+}
+
+// NoMatchError is returned when no command in a group matches the
+// arguments, it explains the mismatches of the closest commands,
+// the commands that matched the most tokens, or the error that
+// prevented testing the commands (ex: an *AbbreviationError)
+type NoMatchError struct {
+	Args       []string   // the supplied arguments
+	Candidates []Mismatch // the mismatches of the closest commands
+
+	// Suggestions for the unknown constants of the closest
+	// commands and for tokens that look like flags but are
+	// not registered in the group (see Suggestion)
+	Suggestions []Suggestion
+
+	// Err is the error of preparing the arguments for testing the
+	// commands (expanding aliases and abbreviations and reading the
+	// output flag), the commands were not tested if it isn't nil
+	Err error
+}
+
+func (e *NoMatchError) Error() string {
+	msg := fmt.Sprintf("invalid command: %s", strings.Join(e.Args, " "))
+
+	switch {
+	case e.Err != nil:
+		msg += ": " + strings.TrimPrefix(e.Err.Error(), "funcv: ")
+	case len(e.Suggestions) > 0:
+		msg += ": " + e.Suggestions[0].String()
+	case len(e.Candidates) > 0:
+		msg += ": " + e.Candidates[0].String()
+	}
+
+	return msg
+}
+
+// Is returns true for ErrNoMatch
+func (e *NoMatchError) Is(target error) bool {
+	return target == ErrNoMatch
+}
+
+// Unwrap returns the error of preparing the arguments
+func (e *NoMatchError) Unwrap() error {
+	return e.Err
+}
+
+// WriteTo will write to the writer a friendly message with the
+// offending token highlighted and the mismatch of each candidate
+func (e *NoMatchError) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+
+	line := strings.Join(e.Args, " ")
+
+	sb.WriteString("invalid command: ")
+	prefix := sb.Len()
+	sb.WriteString(line)
+
+	if len(e.Candidates) > 0 && e.Candidates[0].Pos >= 0 {
+		m := e.Candidates[0]
+		col := prefix + len(strings.Join(e.Args[:m.Pos], " "))
+
+		if m.Pos > 0 {
+			col++
+		}
+
+		width := len(m.Token)
+
+		if width == 0 {
+			width = 1
+		}
+
+		sb.WriteString("\n" + strings.Repeat(" ", col) + strings.Repeat("^", width))
+	}
+
+	if e.Err != nil {
+		sb.WriteString("\n" + strings.TrimPrefix(e.Err.Error(), "funcv: "))
+	}
+
+	for _, m := range e.Candidates {
+		if c, ok := m.Command.(*command); ok && c.desc != "" {
+			sb.WriteString(fmt.Sprintf("\n%s: %s", c.desc, m.String()))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n%s", m.String()))
+		}
+	}
+
+	for _, s := range e.Suggestions {
+		sb.WriteString("\n" + s.String())
+	}
+
+	sb.WriteString("\n")
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+// diagnostics collects the mismatches of the closest commands
+type diagnostics struct {
+	args       []string
+	group      *Group
+	candidates []Mismatch
+	parsed     map[int]bool
+	cause      error // the error of preparing the arguments
+	nm         *NoMatchError
+}
+
+// add adds the mismatch of the command of
+// the given index from the parsing result
+func (d *diagnostics) add(i int, cmd Command, res *Result, err error) {
+	if d.parsed == nil {
+		d.parsed = make(map[int]bool)
+	}
+
+	d.parsed[i] = true
+
+	if isHidden(cmd) {
+		return
+	}
+
+	if res == nil {
+		res = &Result{}
+	}
+
+	d.record(mismatch(i, cmd, d.args, res, err))
+}
+
+// addPruned adds the mismatch of a command that the index pruned
+// (see Group.candidates) without parsing the arguments, its first
+// leading constant that doesn't match them is the mismatch
+func (d *diagnostics) addPruned(i int, cmd Command) {
+	if isHidden(cmd) {
+		return
+	}
+
+	lc := leadingConstants(cmd)
+	k := 0
+
+	for k < len(lc) && k < len(d.args) && lc[k].matches(d.args[k]) {
+		k++
+	}
+
+	if k == len(lc) {
+		return
+	}
+
+	m := Mismatch{Index: i, Command: cmd, Matched: k, Pos: k, Expected: lc[k].String(), Reason: ReasonUnknownConstant, Err: ErrArgNotFound}
+
+	if k < len(d.args) {
+		m.Token = d.args[k]
+	} else {
+		m.Reason = ReasonMissingArgument
+	}
+
+	d.record(m)
+}
+
+// record keeps the mismatch if it is one of the closest
+func (d *diagnostics) record(m Mismatch) {
+	switch {
+	case len(d.candidates) == 0 || m.Matched > d.candidates[0].Matched:
+		d.candidates = []Mismatch{m}
+	case m.Matched == d.candidates[0].Matched:
+		d.candidates = append(d.candidates, m)
+	}
+}
+
+// err returns the no match error, it is built once and only when it
+// is needed (see Group.unmatched), the group's commands that were
+// not parsed (see Group.candidates) are reported by their leading
+// constants, hidden commands are left out
+func (d *diagnostics) err() *NoMatchError {
+	if d.nm != nil {
+		return d.nm
+	}
+
+	if d.cause != nil {
+		d.nm = &NoMatchError{Args: d.args, Err: d.cause}
+		return d.nm
+	}
+
+	if d.group != nil {
+		for i, p := range d.group.pairs {
+			if !d.parsed[i] {
+				d.addPruned(i, p.Cmd)
+			}
+		}
+	}
+
+	e := &NoMatchError{Args: d.args, Candidates: append([]Mismatch(nil), d.candidates...)}
+
+	sort.SliceStable(e.Candidates, func(i, j int) bool {
+		return e.Candidates[i].Index < e.Candidates[j].Index
+	})
+
+	if d.group != nil {
+		d.suggest(e)
+	}
+
+	d.nm = e
+
+	return e
+}
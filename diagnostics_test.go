@@ -0,0 +1,230 @@
+package funcv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNoMatchError(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("delete a file").
+		AddConstant("example", false).
+		AddConstant("delete", false).
+		AddFlag("level", "", new(IntegerConverter), 1).
+		AddVariable("filename", "", new(StringConverter)).
+		ToGroup(&grp, func(int, string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("copy files").
+		AddConstant("example", false).
+		AddConstant("copy", false).
+		AddVariable("count", "", new(IntegerConverter)).
+		AddCheck("count is positive", func(values map[string]interface{}) error {
+			if values["count"].(int64) <= 0 {
+				return errors.New("count is not positive")
+			}
+
+			return nil
+		}).
+		ToGroup(&grp, func(int) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		args     string
+		reason   Reason
+		pos      int
+		token    string
+		expected string
+	}{
+		{"example delet song.mp3", ReasonUnknownConstant, 1, "delet", "delete"},
+		{"example copy x", ReasonInvalidValue, 2, "x", "<count>"},
+		{"example delete --level x a", ReasonInvalidValue, 3, "x", "--level"},
+		{"example delete", ReasonMissingArgument, 2, "", "<filename>"},
+		{"example delete a b", ReasonExtraArguments, 3, "b", ""},
+		{"example copy 0", ReasonFailedCheck, -1, "", ""},
+	}
+
+	for _, c := range cases {
+		_, err := grp.ExecuteBest(strings.Fields(c.args))
+
+		var nm *NoMatchError
+
+		if !errors.As(err, &nm) || !errors.Is(err, ErrNoMatch) || len(nm.Candidates) == 0 {
+			t.Fatal(c.args, err)
+		}
+
+		m := nm.Candidates[0]
+
+		if m.Reason != c.reason || m.Pos != c.pos || m.Token != c.token || m.Expected != c.expected {
+			t.Fatal(c.args, m)
+		}
+	}
+}
+
+func TestNoMatchErrorCandidates(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("delete a file").AddConstant("example", false).AddConstant("delete", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("copy files").AddConstant("example", false).AddConstant("copy", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteBest([]string{"example", "move"})
+
+	if nm := err.(*NoMatchError); len(nm.Candidates) != 2 {
+		t.Fatal(nm.Candidates)
+	}
+}
+
+func TestNoMatchErrorWriteTo(t *testing.T) {
+	var errOut bytes.Buffer
+
+	grp := new(Group).SetErrorOutput(&errOut)
+
+	if err := NewCommand("delete a file").
+		AddConstant("example", false).
+		AddConstant("delete", false).
+		AddVariable("filename", "", new(StringConverter)).
+		ToGroup(grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("copy files").
+		AddConstant("example", false).
+		AddConstant("copy", false).
+		AddVariable("count", "", new(IntegerConverter)).
+		ToGroup(grp, func(int) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.Main([]string{"example", "delet", "song.mp3"}) != ExitUsage {
+		t.FailNow()
+	}
+
+	expected := "invalid command: example delet song.mp3\n" +
+		"                         ^^^^^\n" +
+		"delete a file: unknown command \"delet\", expected \"delete\"\n" +
+		"copy files: unknown command \"delet\", expected \"copy\"\n" +
+		"unknown command \"delet\", did you mean \"delete\"?\n"
+
+	if errOut.String() != expected {
+		t.Fatalf("%q", errOut.String())
+	}
+}
+
+func TestNoMatchErrorWriteToMissing(t *testing.T) {
+	var errOut bytes.Buffer
+
+	grp := new(Group).SetErrorOutput(&errOut)
+
+	if err := NewCommand("delete a file").
+		AddConstant("example", false).
+		AddConstant("delete", false).
+		AddVariable("filename", "", new(StringConverter)).
+		ToGroup(grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.Main([]string{"example", "delete"}) != ExitUsage {
+		t.FailNow()
+	}
+
+	if !strings.Contains(errOut.String(), "example delete\n                                ^\n") {
+		t.Fatalf("%q", errOut.String())
+	}
+}
+
+func TestNoMatchErrorCause(t *testing.T) {
+	var grp Group
+
+	for _, name := range []string{"status", "stash"} {
+		if err := NewCommand(name).AddConstant(name, false).ToGroup(&grp, func() {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var hooked *NoMatchError
+
+	grp.EnableAbbreviations().AddHooks(Hooks{OnNoMatch: func(ctx context.Context, err *NoMatchError) {
+		hooked = err
+	}})
+
+	i, err := grp.ExecuteFirstErr([]string{"st"})
+
+	var ae *AbbreviationError
+
+	if i >= 0 || hooked == nil || !errors.Is(err, ErrNoMatch) || !errors.As(err, &ae) || ae.Token != "st" {
+		t.Fatal(i, err)
+	}
+
+	if s := err.Error(); s != `invalid command: st: ambiguous abbreviation "st" matches "status" or "stash"` {
+		t.Fatal(s)
+	}
+}
+
+func TestNoMatchErrorCauseFallback(t *testing.T) {
+	var grp Group
+
+	for _, name := range []string{"status", "stash"} {
+		if err := NewCommand(name).AddConstant(name, false).ToGroup(&grp, func() {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var fallback *NoMatchError
+
+	grp.EnableAbbreviations().SetFallback(func(ctx context.Context, args []string, err *NoMatchError) error {
+		fallback = err
+		return nil
+	})
+
+	if n := grp.ExecuteAll([]string{"st"}); n != 1 || fallback == nil || fallback.Err == nil {
+		t.Fatal(n, fallback)
+	}
+}
+
+func TestNoMatchErrorPruned(t *testing.T) {
+	var grp Group
+
+	converted := 0
+
+	counting := ConverterFunc[int64](func(arg string) (int64, error) {
+		converted++
+		return 0, ErrInvalidValue
+	})
+
+	if err := NewCommand("resize").AddConstant("resize", false).AddVariable("size", "", counting).ToGroup(&grp, func(int64) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("remove").AddConstant("remove", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteFirstErr([]string{"remove", "x"})
+
+	var nm *NoMatchError
+
+	if !errors.As(err, &nm) || len(nm.Candidates) != 1 || nm.Candidates[0].Index != 1 || nm.Candidates[0].Reason != ReasonExtraArguments {
+		t.Fatal(err)
+	}
+
+	_, err = grp.ExecuteFirstErr([]string{"rm"})
+
+	if !errors.As(err, &nm) || len(nm.Candidates) != 2 || nm.Candidates[0].Reason != ReasonUnknownConstant || nm.Candidates[0].Token != "rm" || nm.Candidates[0].Expected != "resize" {
+		t.Fatal(err)
+	}
+
+	if converted != 0 {
+		t.Fatal(converted)
+	}
+}
@@ -0,0 +1,151 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoMatch is reported when no command in a group
+// matches the supplied arguments (see NoMatchError)
+var ErrNoMatch = errors.New("funcv: no matching command")
+
+// AmbiguityError is returned when more than one command
+// matches the supplied arguments equally well
+type AmbiguityError struct {
+	Args     []string  // the supplied arguments
+	Commands []Command // the tied commands
+	Indexes  []int     // the indexes of the tied commands in the group
+}
+
+func (e *AmbiguityError) Error() string {
+	descs := make([]string, len(e.Commands))
+
+	for i, cmd := range e.Commands {
+		if c, ok := cmd.(*command); ok && c.desc != "" {
+			descs[i] = fmt.Sprintf("%q", c.desc)
+		} else {
+			descs[i] = fmt.Sprintf("#%d", e.Indexes[i])
+		}
+	}
+
+	return fmt.Sprintf("funcv: ambiguous command %q matches %s", strings.Join(e.Args, " "), strings.Join(descs, ", "))
+}
+
+// specificity of a command that matched the arguments
+type specificity struct {
+	constants int  // number of matched constants
+	defaults  int  // number of variables that use their defaults
+	variadic  bool // the command has a variadic argument
+}
+
+func specificityOf(res *Result) specificity {
+	var s specificity
+
+	if res.cmd == nil {
+		return s
+	}
+
+	for _, arg := range res.cmd.args {
+		switch a := arg.(type) {
+		case *constant:
+			s.constants++
+		case *variable:
+			if a.def != nil && !res.Set[a.name] {
+				s.defaults++
+			}
+		case *variadic:
+			s.variadic = true
+		}
+	}
+
+	return s
+}
+
+// compare returns a positive value if s is more specific than
+// o, a negative value if it is less specific and 0 for a tie
+func (s specificity) compare(o specificity) int {
+	switch {
+	case s.constants != o.constants:
+		return s.constants - o.constants
+	case s.defaults != o.defaults:
+		return o.defaults - s.defaults
+	case s.variadic != o.variadic:
+		if o.variadic {
+			return 1
+		}
+
+		return -1
+	}
+
+	return 0
+}
+
+// ExecuteBest parses the supplied arguments against all commands in
+// the group and executes only the most specific matching command,
+// the one with more constants, then fewer variables that use their
+// defaults, then without a variadic argument, the executed command's
+// index is returned with the error of its action function, if no
+// command matches the error of the default or the fallback action
+// (see SetDefault and SetFallback), or a *NoMatchError if there is
+// none, is returned and if the most specific commands are tied an
+// *AmbiguityError is returned, in both cases with a negative index
+// (IndexHandled if the default or the fallback action succeeded)
+func (g *Group) ExecuteBest(args []string) (int, error) {
+	return g.ExecuteBestContext(context.Background(), args)
+}
+
+// ExecuteBestContext is the same as ExecuteBest but with a context
+// that is passed to the action function (see Command.ExecuteContext)
+func (g *Group) ExecuteBestContext(ctx context.Context, args []string) (int, error) {
+	var best []int
+	var results []*Result
+	var top specificity
+
+	e, diag, candidates := g.start(ctx, args)
+	rest := diag.args
+
+	for _, i := range candidates {
+		p := g.pairs[i]
+
+		if err := ctx.Err(); err != nil {
+			return -1, err
+		}
+
+		res, err := e.parse(ctx, p.Cmd, rest)
+
+		if err != nil {
+			diag.add(i, p.Cmd, res, err)
+			continue
+		}
+
+		s := specificityOf(res)
+
+		switch cmp := s.compare(top); {
+		case len(best) == 0 || cmp > 0:
+			best, results, top = []int{i}, []*Result{res}, s
+		case cmp == 0:
+			best, results = append(best, i), append(results, res)
+		}
+	}
+
+	switch len(best) {
+	case 0:
+		if _, err := g.unmatched(ctx, e, diag, true); err != nil {
+			return IndexNotFound, err
+		}
+
+		return IndexHandled, nil
+	case 1:
+		return best[0], results[0].execute(ctx, e, g.pairs[best[0]].Fn)
+	}
+
+	ae := &AmbiguityError{Args: rest, Indexes: best}
+
+	for _, i := range best {
+		ae.Commands = append(ae.Commands, g.pairs[i].Cmd)
+	}
+
+	return -1, ae
+}
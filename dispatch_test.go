@@ -0,0 +1,83 @@
+package funcv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExecuteBest(t *testing.T) {
+	var grp Group
+	var called string
+
+	conv := new(IntegerConverter)
+
+	// added first, less specific
+	if err := NewCommand("add two or more numbers").
+		AddConstant("add", false).
+		AddVariable("1st", "", conv).
+		AddVariable("2nd", "", conv).
+		AddVariadic("operands", "", conv).
+		ToGroup(&grp, func(operands ...int) { called = "variadic" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("add two numbers").
+		AddConstant("add", false).
+		AddVariable("1st", "", conv).
+		AddVariableWithDefault("2nd", "", conv, int64(0)).
+		ToGroup(&grp, func(x, y int) { called = "two" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("add one number").
+		AddConstant("add", false).
+		AddVariable("1st", "", conv).
+		ToGroup(&grp, func(x int) { called = "one" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("add zero").
+		AddConstant("add", false).
+		AddConstant("0", false).
+		ToGroup(&grp, func() { called = "zero" }); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"add 1 2":   "two",
+		"add 1 2 3": "variadic",
+		"add 1":     "one",
+		"add 0":     "zero",
+	}
+
+	for args, expected := range cases {
+		called = ""
+
+		if i, err := grp.ExecuteBest(strings.Fields(args)); err != nil || i < 0 || called != expected {
+			t.Fatal(args, i, err, called)
+		}
+	}
+
+	if i, err := grp.ExecuteBest([]string{"sub"}); i >= 0 || !errors.Is(err, ErrNoMatch) {
+		t.Fatal(i, err)
+	}
+}
+
+func TestExecuteBestAmbiguity(t *testing.T) {
+	var grp Group
+
+	for _, desc := range []string{"first", "second"} {
+		if err := NewCommand(desc).AddConstant("test", false).AddVariable("v", "", new(StringConverter)).ToGroup(&grp, func(v string) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := grp.ExecuteBest([]string{"test", "x"})
+
+	var ae *AmbiguityError
+
+	if !errors.As(err, &ae) || len(ae.Commands) != 2 || ae.Indexes[1] != 1 || !strings.Contains(err.Error(), `"first", "second"`) {
+		t.Fatal(err)
+	}
+}
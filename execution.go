@@ -0,0 +1,71 @@
+package funcv
+
+import (
+	"context"
+	"io"
+)
+
+// execution holds the settings of parsing and executing commands,
+// the settings of a context (see WithOutput, WithRecovery,
+// WithExperimental and WithWarnings) and of the executing group,
+// a group's settings are kept out of the context that is passed
+// to the action functions so that a command that an action function
+// executes doesn't inherit them
+type execution struct {
+	registry     *Registry
+	interceptors *interceptors
+	output       *output
+	recovery     bool
+	features     map[string]bool
+	warnings     io.Writer
+}
+
+// executionOf returns the settings of executing
+// commands with ctx (without a group)
+func executionOf(ctx context.Context) *execution {
+	return &execution{
+		interceptors: &interceptors{},
+		output:       outputFrom(ctx),
+		recovery:     recoveryFrom(ctx),
+		features:     featuresFrom(ctx),
+		warnings:     warningsFrom(ctx),
+	}
+}
+
+// enable returns the enabled features with the given features
+func (e *execution) enable(features ...string) map[string]bool {
+	enabled := make(map[string]bool)
+
+	for f := range e.features {
+		enabled[f] = true
+	}
+
+	for _, f := range features {
+		enabled[f] = true
+	}
+
+	return enabled
+}
+
+// handle binds the invocation's action function and calls it, unless
+// ctx is done, a panic of the function is recovered if recovery is
+// enabled (see WithRecovery), a command that is implemented outside
+// the package executes the function itself
+func (e *execution) handle(ctx context.Context, inv *Invocation) ([]interface{}, error) {
+	if res := inv.Result; res.cmd == nil {
+		_, err := res.ext.ExecuteContext(ctx, res.args, inv.Fn)
+		return nil, err
+	}
+
+	b, err := inv.Result.bind(ctx, inv.Fn, e.registry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return b.call(e.recovery, inv.Result.cmd.desc)
+}
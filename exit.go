@@ -0,0 +1,157 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// Exit codes that are returned by Group.Main
+const (
+	ExitOK      = 0 // the action function succeeded
+	ExitFailure = 1 // the action function failed
+	ExitUsage   = 2 // no command matched the arguments
+)
+
+// ExitCoder is implemented by errors that determine the
+// exit code of the process (see Group.Main)
+type ExitCoder interface {
+	ExitCode() int
+}
+
+type exitCode struct {
+	target interface{}
+	code   int
+}
+
+// match returns true if err matches the target
+func (e exitCode) match(err error) bool {
+	if target, ok := e.target.(error); ok {
+		return errors.Is(err, target)
+	}
+
+	return errors.As(err, reflect.New(reflect.TypeOf(e.target).Elem()).Interface())
+}
+
+// SetErrorOutput sets the writer of the errors that Main prints
+// and of the deprecation warnings (os.Stderr by default)
+func (g *Group) SetErrorOutput(w io.Writer) *Group {
+	g.errOut = w
+	return g
+}
+
+// MapExitCode maps the errors that match target to the exit code,
+// target is either an error that is matched by errors.Is or a
+// pointer to a type that is matched by errors.As (ex: a pointer to
+// *os.PathError), the mappings are tested by their order, an
+// invalid target panics
+func (g *Group) MapExitCode(target interface{}, code int) *Group {
+	if _, ok := target.(error); !ok {
+		t := reflect.TypeOf(target)
+
+		if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface && !t.Elem().Implements(errorType) {
+			panic(fmt.Sprintf("funcv: invalid exit code target %v", t))
+		}
+	}
+
+	g.exitCodes = append(g.exitCodes, exitCode{target, code})
+	return g
+}
+
+// ExitCode returns the exit code of an error returned by an action
+// function, ExitOK for nil, the code of the first ExitCoder in the
+// error's chain, the code of the first matching mapping (see
+// MapExitCode) or ExitFailure
+func (g *Group) ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var coder ExitCoder
+
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	for _, e := range g.exitCodes {
+		if e.match(err) {
+			return e.code
+		}
+	}
+
+	return ExitFailure
+}
+
+// Main executes the first command in the group that matches the
+// arguments and returns the process exit code, if no command matches,
+// the default or the fallback action is called (see SetDefault and
+// SetFallback) or, if there is none, the mismatches are printed (see
+// NoMatchError) and ExitUsage is returned, if the action function
+// fails, its error is printed and its exit code is returned (see
+// ExitCode):
+//
+//	os.Exit(grp.Main(os.Args[1:]))
+func (g *Group) Main(args []string) int {
+	return g.MainContext(context.Background(), args)
+}
+
+// MainContext is the same as Main but with a context that is
+// passed to the action functions (see Command.ExecuteContext)
+func (g *Group) MainContext(ctx context.Context, args []string) int {
+	e, diag, candidates := g.start(ctx, args)
+	rest := diag.args
+
+	for _, i := range candidates {
+		p := g.pairs[i]
+
+		if err := ctx.Err(); err != nil {
+			g.printError(err)
+			return g.ExitCode(err)
+		}
+
+		res, err := e.parse(ctx, p.Cmd, rest)
+
+		if err != nil {
+			diag.add(i, p.Cmd, res, err)
+			continue
+		}
+
+		if err := res.execute(ctx, e, p.Fn); err != nil {
+			g.printError(err)
+			return g.ExitCode(err)
+		}
+
+		return ExitOK
+	}
+
+	handled, err := g.unmatched(ctx, e, diag, true)
+
+	switch {
+	case !handled:
+		g.printError(err)
+		return ExitUsage
+	case err != nil:
+		g.printError(err)
+		return g.ExitCode(err)
+	}
+
+	return ExitOK
+}
+
+func (g *Group) printError(err error) {
+	w := g.errOut
+
+	if w == nil {
+		w = os.Stderr
+	}
+
+	if wt, ok := err.(io.WriterTo); ok {
+		wt.WriteTo(w)
+		return
+	}
+
+	fmt.Fprintln(w, err)
+}
@@ -0,0 +1,81 @@
+package funcv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+type codeError int
+
+func (e codeError) Error() string {
+	return "code error"
+}
+
+func (e codeError) ExitCode() int {
+	return int(e)
+}
+
+func TestGroupMain000(t *testing.T) {
+	var grp Group
+	var errOut bytes.Buffer
+
+	errNotFound := errors.New("not found")
+
+	grp.SetErrorOutput(&errOut).
+		MapExitCode(errNotFound, 3).
+		MapExitCode(new(*os.PathError), 4)
+
+	if err := NewCommand("").AddConstant("ok", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("fail", false).AddVariable("v", "", new(StringConverter)).ToGroup(&grp, func(v string) error {
+		switch v {
+		case "coder":
+			return codeError(5)
+		case "found":
+			return errNotFound
+		case "path":
+			_, err := os.Open("/no/such/file")
+			return err
+		}
+
+		return errors.New(v)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		args []string
+		code int
+		out  string
+	}{
+		{[]string{"ok"}, ExitOK, ""},
+		{[]string{"ok", "x"}, ExitUsage, "invalid command: ok x"},
+		{[]string{"fail", "coder"}, 5, "code error"},
+		{[]string{"fail", "found"}, 3, "not found"},
+		{[]string{"fail", "path"}, 4, "/no/such/file"},
+		{[]string{"fail", "other"}, ExitFailure, "other"},
+	}
+
+	for _, c := range cases {
+		errOut.Reset()
+
+		if code := grp.Main(c.args); code != c.code || !strings.Contains(errOut.String(), c.out) {
+			t.Fatal(c.args, code, errOut.String())
+		}
+	}
+}
+
+func TestGroupMain001(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.FailNow()
+		}
+	}()
+
+	new(Group).MapExitCode(5, 1)
+}
@@ -0,0 +1,104 @@
+package funcv
+
+import (
+	"context"
+)
+
+// Indexes that are returned instead of a command's index
+// when no command in the group executed successfully
+const (
+	IndexNotFound = -1 // no command matched, or the matched commands failed
+	IndexHandled  = -2 // the default or the fallback action (or a plugin) succeeded
+)
+
+// SetDefault sets the action that is called when the arguments are
+// only the common prefix of the group's commands (the leading
+// constants all of them start with, ex: the program name) and no
+// command matches them, a group of less than two commands has no
+// common prefix
+func (g *Group) SetDefault(fn func(ctx context.Context) error) *Group {
+	g.defaultFn = fn
+	return g
+}
+
+// SetFallback sets the action that is called when no command
+// matches the arguments (and the default action doesn't apply),
+// with the arguments and the mismatches of the closest commands
+func (g *Group) SetFallback(fn func(ctx context.Context, args []string, err *NoMatchError) error) *Group {
+	g.fallbackFn = fn
+	return g
+}
+
+// commonPrefix returns the leading constants that all the commands
+// in the group start with, ok is false if there are less than two
+// commands
+func (g *Group) commonPrefix() (prefix []*constant, ok bool) {
+	if len(g.pairs) < 2 {
+		return nil, false
+	}
+
+	for i, p := range g.pairs {
+		lc := leadingConstants(p.Cmd)
+
+		if i == 0 {
+			prefix = lc
+			continue
+		}
+
+		n := 0
+
+		for n < len(prefix) && n < len(lc) && *prefix[n] == *lc[n] {
+			n++
+		}
+
+		prefix = prefix[:n]
+	}
+
+	return prefix, true
+}
+
+// isDefault returns true if the arguments
+// are the common prefix of the group's commands
+func (g *Group) isDefault(args []string) bool {
+	prefix, ok := g.commonPrefix()
+
+	if !ok || len(args) != len(prefix) {
+		return false
+	}
+
+	for i, c := range prefix {
+		if !c.matches(args[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unmatched handles arguments that no command matches, it calls the
+// OnNoMatch hooks and then the default action, a plugin (see Plugins)
+// or the fallback action and returns its error, handled is false if
+// there is no suitable action and then the error is a *NoMatchError,
+// or ErrNoMatch if report is false, the *NoMatchError is built only
+// for the hooks, the fallback action or a report
+func (g *Group) unmatched(ctx context.Context, e *execution, diag *diagnostics, report bool) (handled bool, err error) {
+	e.noMatch(ctx, diag)
+
+	if g.defaultFn != nil && g.isDefault(diag.args) {
+		return true, g.defaultFn(ctx)
+	}
+
+	if found, err := g.plugin(ctx, diag.args); found {
+		return true, err
+	}
+
+	if g.fallbackFn != nil {
+		return true, g.fallbackFn(ctx, diag.args, diag.err())
+	}
+
+	if !report {
+		return false, ErrNoMatch
+	}
+
+	return false, diag.err()
+}
@@ -0,0 +1,236 @@
+package funcv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if n := grp.ExecuteAll([]string{"example"}); n != 1 || !called {
+		t.Fatal(n, called)
+	}
+}
+
+func TestDefaultFirst(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if i := grp.ExecuteFirst([]string{"example"}); i != IndexHandled || !called {
+		t.Fatal(i, called)
+	}
+}
+
+func TestDefaultMain(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if grp.Main([]string{"example"}) != ExitOK || !called {
+		t.Fatal(called)
+	}
+}
+
+func TestDefaultBest(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if commonPrefix, ok := grp.commonPrefix(); !ok || len(commonPrefix) != 1 || commonPrefix[0].text != "example" {
+		t.Fatal(commonPrefix)
+	}
+
+	if i, err := grp.ExecuteBest([]string{"example"}); i != IndexHandled || err != nil || !called {
+		t.Fatal(i, err, called)
+	}
+}
+
+func TestDefaultOtherArgs(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	for _, args := range [][]string{{"example", "x"}, {}, {"other"}} {
+		if n := grp.ExecuteAll(args); n != 0 || called {
+			t.Fatal(args, n, called)
+		}
+	}
+}
+
+func TestDefaultSingleCommand(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	_, err := grp.ExecuteBest([]string{"example", "delete"})
+
+	var nm *NoMatchError
+
+	if !errors.As(err, &nm) || len(nm.Candidates) != 1 || nm.Candidates[0].Reason != ReasonMissingArgument || called {
+		t.Fatal(err, called)
+	}
+}
+
+func TestDefaultSingleCommandFirst(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+
+	grp.SetDefault(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if i := grp.ExecuteFirst([]string{"example", "delete"}); i != IndexNotFound || called {
+		t.Fatal(i, called)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("delete", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	errFallback := errors.New("fallback")
+
+	var args []string
+	var nm *NoMatchError
+
+	grp.SetFallback(func(ctx context.Context, a []string, err *NoMatchError) error {
+		args, nm = a, err
+		return errFallback
+	})
+
+	if _, err := grp.ExecuteBest([]string{"example", "delet", "x"}); err != errFallback {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(args, []string{"example", "delet", "x"}) || nm == nil || len(nm.Candidates) != 2 {
+		t.Fatal(args, nm)
+	}
+}
+
+func TestFallbackMain(t *testing.T) {
+	var errOut bytes.Buffer
+
+	grp := new(Group).SetErrorOutput(&errOut)
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	grp.SetFallback(func(ctx context.Context, a []string, err *NoMatchError) error {
+		return errors.New("fallback")
+	})
+
+	if grp.Main([]string{"example"}) != ExitFailure || errOut.String() != "fallback\n" {
+		t.Fatal(errOut.String())
+	}
+}
+
+func TestFallbackHandled(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("").AddConstant("example", false).AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	grp.SetFallback(func(ctx context.Context, a []string, err *NoMatchError) error {
+		return nil
+	})
+
+	if n := grp.ExecuteAll([]string{"other"}); n != 1 {
+		t.Fatal(n)
+	}
+}
@@ -40,21 +40,21 @@ func extractFlagName(arg string) string {
 
 type flagsBuilder struct {
 	converters map[string]Converter
-	values     map[string]interface{}
 	founddefs  map[string]interface{}
 	defaults   map[string]interface{}
 	flags      []string
 	desc       []string
+	meta       map[string]metadata
 	command    *command
 }
 
-func (b *flagsBuilder) toParams() ([]interface{}, error) {
+func (b *flagsBuilder) toParams(values map[string]interface{}) ([]interface{}, error) {
 
 	var params []interface{}
 
 	for _, name := range b.flags {
 
-		v, found := b.values[name]
+		v, found := values[name]
 
 		if !found {
 			return nil, fmt.Errorf("funcv: flag %s not found", name)
@@ -66,39 +66,49 @@ func (b *flagsBuilder) toParams() ([]interface{}, error) {
 	return params, nil
 }
 
+// Extract the flags values, the builder is not modified so
+// that a compiled command can be executed concurrently
 func (b *flagsBuilder) Extract(args []string) ([]string, []interface{}, error) {
+	args, params, _, err := b.extract(args)
+	return args, params, err
+}
+
+// extract is the same as Extract but also returns, for each
+// flag, whether it was found in the arguments
+func (b *flagsBuilder) extract(args []string) ([]string, []interface{}, map[string]bool, error) {
+	values := make(map[string]interface{}, len(b.defaults))
+	set := make(map[string]bool, len(b.flags))
+
+	for name, def := range b.defaults {
+		values[name] = def
+		set[name] = false
+	}
 
 	for len(args) > 0 {
 
 		name := extractFlagName(args[0])
 
 		if name == "" {
-			params, err := b.toParams()
-			return args, params, err
-
+			break
 		}
 
-		if _, found := b.values[name]; !found {
-			params, err := b.toParams()
-			return args, params, err
+		conv, found := b.converters[name]
+
+		if !found {
+			break
 		}
 
 		if def, found := b.founddefs[name]; found {
-			b.values[name] = def
+			values[name] = def
 		} else {
-			delete(b.values, name)
+			delete(values, name)
 		}
 
+		set[name] = true
+
 		args = args[1:]
 
 		var v string
-
-		conv, found := b.converters[name]
-
-		if !found {
-			return args, nil, fmt.Errorf("funcv: missing converter for %s", name)
-		}
-
 		var i int
 
 		if len(args) > 0 {
@@ -111,7 +121,9 @@ func (b *flagsBuilder) Extract(args []string) ([]string, []interface{}, error) {
 		}
 
 		if conval, err := conv.Convert(v); err == nil {
-			b.values[name] = conval
+			values[name] = conval
+		} else if _, found := b.founddefs[name]; !found && v != "" {
+			return args, nil, set, err
 		} else {
 			i = 0
 		}
@@ -119,8 +131,49 @@ func (b *flagsBuilder) Extract(args []string) ([]string, []interface{}, error) {
 		args = args[i:]
 	}
 
-	params, err := b.toParams()
-	return args, params, err
+	params, err := b.toParams(values)
+	return args, params, set, err
+}
+
+// close adds the flags to the command's arguments,
+// once, when the next argument is added or on compile
+func (b *flagsBuilder) close() {
+	if l := len(b.command.args); l == 0 || b.command.args[l-1] != Argument(b) {
+		b.command.args = append(b.command.args, b)
+	}
+}
+
+// clone returns a copy of the builder that belongs to the given command
+func (b *flagsBuilder) clone(c *command) *flagsBuilder {
+	cp := &flagsBuilder{
+		converters: make(map[string]Converter, len(b.converters)),
+		founddefs:  make(map[string]interface{}, len(b.founddefs)),
+		defaults:   make(map[string]interface{}, len(b.defaults)),
+		flags:      append([]string(nil), b.flags...),
+		desc:       append([]string(nil), b.desc...),
+		command:    c}
+
+	for k, v := range b.converters {
+		cp.converters[k] = v
+	}
+
+	for k, v := range b.founddefs {
+		cp.founddefs[k] = v
+	}
+
+	for k, v := range b.defaults {
+		cp.defaults[k] = v
+	}
+
+	if b.meta != nil {
+		cp.meta = make(map[string]metadata, len(b.meta))
+
+		for k, v := range b.meta {
+			cp.meta[k] = v
+		}
+	}
+
+	return cp
 }
 
 func (b *flagsBuilder) AddFlag(name, desc string, conv Converter, def interface{}) Builder {
@@ -145,7 +198,6 @@ func (b *flagsBuilder) AddFlag(name, desc string, conv Converter, def interface{
 
 	b.desc = append(b.desc, desc)
 	b.flags = append(b.flags, name)
-	b.values[name] = def
 	b.defaults[name] = def
 	b.converters[name] = conv
 	return b
@@ -173,7 +225,6 @@ func (b *flagsBuilder) AddParameterlessFlag(name, desc string, conv Converter, f
 
 	b.desc = append(b.desc, desc)
 	b.flags = append(b.flags, name)
-	b.values[name] = missing
 	b.defaults[name] = missing
 	b.founddefs[name] = found
 	b.converters[name] = conv
@@ -185,7 +236,7 @@ func (b *flagsBuilder) AddConstant(text string, insensitive bool) Builder {
 		return b
 	}
 
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.AddConstant(text, insensitive)
 }
 
@@ -194,7 +245,7 @@ func (b *flagsBuilder) AddVariable(name, desc string, conv Converter) Builder {
 		return b
 	}
 
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.AddVariable(name, desc, conv)
 }
 
@@ -203,7 +254,7 @@ func (b *flagsBuilder) AddVariableWithDefault(name, desc string, conv Converter,
 		return b
 	}
 
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.AddVariableWithDefault(name, desc, conv, def)
 }
 
@@ -212,7 +263,7 @@ func (b *flagsBuilder) AddArgument(arg Argument) Builder {
 		return b
 	}
 
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.AddArgument(arg)
 }
 
@@ -221,22 +272,63 @@ func (b *flagsBuilder) AddVariadic(name, desc string, conv Converter) Compiler {
 		return b
 	}
 
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.AddVariadic(name, desc, conv)
 }
 
+func (b *flagsBuilder) AddCheck(desc string, fn func(values map[string]interface{}) error) Compiler {
+	if b.command.err != nil {
+		return b
+	}
+
+	b.close()
+	return b.command.AddCheck(desc, fn)
+}
+
+func (b *flagsBuilder) Provide(values ...interface{}) Compiler {
+	if b.command.err != nil {
+		return b
+	}
+
+	b.close()
+	return b.command.Provide(values...)
+}
+
+func (b *flagsBuilder) Use(mw ...Middleware) Compiler {
+	if b.command.err != nil {
+		return b
+	}
+
+	b.close()
+	return b.command.Use(mw...)
+}
+
+func (b *flagsBuilder) AddHooks(hooks Hooks) Compiler {
+	if b.command.err != nil {
+		return b
+	}
+
+	b.close()
+	return b.command.AddHooks(hooks)
+}
+
 func (b *flagsBuilder) Compile() (Command, error) {
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.Compile()
 }
 
 func (b *flagsBuilder) MustCompile() Command {
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.MustCompile()
 }
 
+func (b *flagsBuilder) CompileFor(fn interface{}) (Command, error) {
+	b.close()
+	return b.command.CompileFor(fn)
+}
+
 func (b *flagsBuilder) ToGroup(grp *Group, fn interface{}) error {
-	b.command.args = append(b.command.args, b)
+	b.close()
 	return b.command.ToGroup(grp, fn)
 }
 
@@ -244,9 +336,13 @@ func (b *flagsBuilder) WriteTo(w io.Writer) (int64, error) {
 	var written int64
 
 	for i, name := range b.flags {
+		if b.meta[name].hidden {
+			continue
+		}
+
 		def, _ := b.defaults[name]
 
-		if n, err := fmt.Fprintf(w, "\n\t%s\t%s (default: %v)", toFlag(name), b.desc[i], def); err == nil {
+		if n, err := fmt.Fprintf(w, "\n\t%s\t%s (default: %v)%s", toFlag(name), describe(b.desc[i], b.converters[name]), def, b.meta[name].notes()); err == nil {
 			written += int64(n)
 		} else {
 			return written + int64(n), err
@@ -257,15 +353,17 @@ func (b *flagsBuilder) WriteTo(w io.Writer) (int64, error) {
 }
 
 func (b *flagsBuilder) String() string {
-	var sb strings.Builder
-
-	for i, name := range b.flags {
-		sb.WriteString(fmt.Sprintf("[%s]", toFlag(name)))
+	var flags []string
 
-		if i+1 < len(b.flags) {
-			sb.WriteString(" ")
+	for _, name := range b.flags {
+		if !b.meta[name].hidden {
+			flags = append(flags, fmt.Sprintf("[%s]", toFlag(name)))
 		}
 	}
 
-	return sb.String()
+	return strings.Join(flags, " ")
+}
+
+func (b *flagsBuilder) paramNames() []string {
+	return b.flags
 }
@@ -9,6 +9,7 @@
 package funcv
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -26,6 +27,12 @@ var (
 
 	// ErrInvalidValue in supplied arguments
 	ErrInvalidValue = errors.New("funcv: invalid value")
+
+	// ErrLossyConversion of a value to the action function's parameter type
+	ErrLossyConversion = errors.New("funcv: lossy conversion")
+
+	// ErrExperimental command or flag that isn't enabled
+	ErrExperimental = errors.New("funcv: experimental feature is not enabled")
 )
 
 // ConstantAdder is used to add a constant to a command, constants
@@ -55,6 +62,15 @@ type FlagAdder interface {
 	AddFlag(name, desc string, conv Converter, def interface{}) Builder
 	// AddParameterlessFlag adds a flag that doesn't require a parameter (like boolean flags)
 	AddParameterlessFlag(name, desc string, conv Converter, found, missing interface{}) Builder
+	// HideFlag hides an added flag from usage texts,
+	// the flag is still extracted
+	HideFlag(name string) Builder
+	// DeprecateFlag marks an added flag as deprecated, using
+	// it writes a warning that names the replacement (if any)
+	DeprecateFlag(name, replacement string) Builder
+	// ExperimentalFlag makes using an added flag require
+	// the feature to be enabled (see WithExperimental)
+	ExperimentalFlag(name, feature string) Builder
 }
 
 // ArgumentAdder can be used to add any custom argument
@@ -91,10 +107,41 @@ type Compiler interface {
 	// MustCompile is the same as Compile
 	// but will panic if the compilation failed
 	MustCompile() Command
+	// CompileFor is the same as Compile but also
+	// checks that the given action function is
+	// compatible with the command's arguments
+	CompileFor(fn interface{}) (Command, error)
 	// ToGroup compiles and adds the command and
 	// the given action function to a group, returns
-	// an error if the compilation failed
+	// an error if the compilation failed or if the
+	// function is not compatible with the command
 	ToGroup(grp *Group, fn interface{}) error
+	// AddCheck adds a validation of the extracted values,
+	// fn receives the values by their argument names (a
+	// variadic argument's value is a slice) and returns a
+	// non-nil error if the values are invalid, desc
+	// describes the check in usage texts
+	AddCheck(desc string, fn func(values map[string]interface{}) error) Compiler
+	// Provide registers values that are injected into the
+	// command's action functions (see Registry.Provide), the
+	// command's values precede the values provided by a group
+	Provide(values ...interface{}) Compiler
+	// Use adds middleware around the command's action
+	// functions, after the middleware of a group (see
+	// Middleware), the first middleware is the outermost
+	Use(mw ...Middleware) Compiler
+	// AddHooks adds hooks around the command's
+	// execution (see Hooks)
+	AddHooks(hooks Hooks) Compiler
+	// Hide hides the command from usage texts and
+	// suggestions, the command still executes
+	Hide() Compiler
+	// Deprecate marks the command as deprecated, executing
+	// it writes a warning that names the replacement (if any)
+	Deprecate(replacement string) Compiler
+	// Experimental makes executing the command require the
+	// feature to be enabled (see WithExperimental)
+	Experimental(feature string) Compiler
 }
 
 // Command represents a textual command that can be later
@@ -109,6 +156,29 @@ type Command interface {
 	// need to be compatible with the command's arguments or else
 	// a non-nil error is returned
 	Execute(args []string, fn interface{}) (int, error)
+	// ExecuteContext is the same as Execute, action
+	// function parameters of type context.Context receive
+	// ctx, parameters of type Command receive the command
+	// and parameters of provided types receive the provided
+	// values (such parameters don't count as one of the
+	// command's parameters), the function is not called
+	// if ctx is done, the function's return values other
+	// than the last error are rendered if ctx has an
+	// output (see WithOutput) and a panic of the function
+	// is returned as a *PanicError if ctx is with recovery
+	// (see WithRecovery), deprecation warnings are written
+	// to the writer of ctx (see WithWarnings)
+	ExecuteContext(ctx context.Context, args []string, fn interface{}) (int, error)
+	// Parse tests the supplied arguments against the
+	// command without calling an action function, the
+	// result holds the extracted values and can bind an
+	// action function to them, the result is returned
+	// (with the number of valid arguments) also when
+	// the arguments are not compatible with the command, a
+	// group executes a Command that is implemented outside
+	// the package (its result has no extracted values) by
+	// calling its ExecuteContext with the arguments
+	Parse(args []string) (*Result, error)
 	io.WriterTo
 }
 
@@ -134,6 +204,15 @@ type Converter interface {
 	IsSupported(v interface{}) bool
 }
 
+// ConstraintDescriber is implemented by converters that restrict
+// their input values, the description is added to the usage text
+// of the arguments that use the converter
+type ConstraintDescriber interface {
+	// Constraints returns a short description of the
+	// constraints (ex: "1..65535") or an empty string
+	Constraints() string
+}
+
 // NewCommand returns a builder that is used for
 // building a new command
 func NewCommand(desc string) Builder {
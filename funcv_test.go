@@ -2,6 +2,9 @@ package funcv
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -814,9 +817,21 @@ func TestFloatFlag000(t *testing.T) {
 func TestFloatFlag001(t *testing.T) {
 	c := NewCommand("").AddFlag("x", "", new(FloatConverter), 0).MustCompile()
 
+	_, err := c.Execute([]string{"-x", "1.2"}, func(a int) {
+		t.Fatal("truncated value", a)
+	})
+
+	if !errors.Is(err, ErrLossyConversion) {
+		t.Fatal(err)
+	}
+}
+
+func TestFloatFlag002(t *testing.T) {
+	c := NewCommand("").AddFlag("x", "", new(FloatConverter), 0).MustCompile()
+
 	var v int
 
-	_, err := c.Execute([]string{"-x", "1.2"}, func(a int) {
+	_, err := c.Execute([]string{"-x", "2.0"}, func(a int) {
 		v = a
 	})
 
@@ -824,11 +839,81 @@ func TestFloatFlag001(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if v != 1 {
+	if v != 2 {
 		t.Fatal("wrong value", v)
 	}
 }
 
+func TestOverflow000(t *testing.T) {
+	c := NewCommand("").AddVariable("size", "", new(IntegerConverter)).MustCompile()
+
+	_, err := c.Execute([]string{"300"}, func(a uint8) {
+		t.Fatal("overflowed value", a)
+	})
+
+	if !errors.Is(err, ErrLossyConversion) || !strings.Contains(err.Error(), "size") {
+		t.Fatal(err)
+	}
+}
+
+func TestOverflow001(t *testing.T) {
+	c := NewCommand("").AddVariadic("values", "", new(IntegerConverter)).MustCompile()
+
+	_, err := c.Execute([]string{"1", "-1"}, func(a ...uint) {
+		t.Fatal("sign lost", a)
+	})
+
+	if !errors.Is(err, ErrLossyConversion) {
+		t.Fatal(err)
+	}
+
+	var v []int8
+
+	if _, err := c.Execute([]string{"1", "-128"}, func(a ...int8) { v = a }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(v) != 2 || v[1] != -128 {
+		t.Fatal("wrong value", v)
+	}
+}
+
+func TestRounding000(t *testing.T) {
+	c := NewCommand("").AddVariable("n", "", new(IntegerConverter)).MustCompile()
+
+	_, err := c.Execute([]string{"9007199254740993"}, func(a float64) {
+		t.Fatal("rounded value", a)
+	})
+
+	if !errors.Is(err, ErrLossyConversion) {
+		t.Fatal(err)
+	}
+
+	var v float32
+
+	if _, err := c.Execute([]string{"16777216"}, func(a float32) { v = a }); err != nil || v != 16777216 {
+		t.Fatal(err, v)
+	}
+}
+
+func TestRounding001(t *testing.T) {
+	c := NewCommand("").AddVariable("x", "", new(FloatConverter)).MustCompile()
+
+	_, err := c.Execute([]string{"1e39"}, func(a float32) {
+		t.Fatal("overflowed value", a)
+	})
+
+	if !errors.Is(err, ErrLossyConversion) {
+		t.Fatal(err)
+	}
+
+	var v float32
+
+	if _, err := c.Execute([]string{"0.1"}, func(a float32) { v = a }); err != nil || v != 0.1 {
+		t.Fatal(err, v)
+	}
+}
+
 func TestErrReturn(t *testing.T) {
 	c := NewCommand("").AddConstant("test", false).MustCompile()
 
@@ -842,3 +927,89 @@ func TestErrReturn(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestFlagStateLeak(t *testing.T) {
+	c := NewCommand("").
+		AddParameterlessFlag("b", "", new(BooleanConverter), true, false).
+		AddFlag("s", "", new(StringConverter), "def").
+		MustCompile()
+
+	if _, err := c.Execute([]string{"-b", "-s", "xyz"}, func(b bool, s string) {
+		if !b || s != "xyz" {
+			t.Fatal("wrong values", b, s)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Execute([]string{}, func(b bool, s string) {
+		if b || s != "def" {
+			t.Fatal("leaked values", b, s)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompiledImmutable(t *testing.T) {
+	b := NewCommand("").AddConstant("test", false)
+	c := b.MustCompile()
+
+	b.AddVariable("v", "", new(StringConverter))
+
+	if _, err := c.Execute([]string{"test"}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentExecute(t *testing.T) {
+	c := NewCommand("").
+		AddConstant("test", false).
+		AddFlag("n", "", new(IntegerConverter), int64(-1)).
+		AddVariable("v", "", new(IntegerConverter)).
+		MustCompile()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			args := []string{"test", strconv.Itoa(i)}
+			want := int64(-1)
+
+			if i%2 == 0 {
+				args = []string{"test", "-n", strconv.Itoa(i), strconv.Itoa(i)}
+				want = int64(i)
+			}
+
+			if _, err := c.Execute(args, func(n, v int64) {
+				if n != want || v != int64(i) {
+					t.Error("wrong values", n, v, i)
+				}
+			}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestNewGroup(t *testing.T) {
+	called := false
+
+	grp := NewGroup(
+		Pair{NewCommand("").AddConstant("a", false).MustCompile(), func() {}},
+		Pair{NewCommand("").AddConstant("b", false).MustCompile(), func() { called = true }})
+
+	if grp.Len() != 2 || grp.Pairs()[1].Fn == nil {
+		t.FailNow()
+	}
+
+	if grp.ExecuteFirst([]string{"b"}) != 1 || !called {
+		t.FailNow()
+	}
+}
@@ -1,9 +1,16 @@
 package funcv
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"reflect"
+	"sort"
+	"strings"
+	"syscall"
 )
 
 // Pair of a command and an action function
@@ -12,15 +19,332 @@ type Pair struct {
 	Fn  interface{}
 }
 
-// Group of commands with binded action functions
-type Group []Pair
+// Group of commands with binded action functions,
+// the zero value is an empty group ready to use, the
+// commands are indexed by their leading constants (the
+// constants before their first other argument) so that
+// only the commands that can match the arguments are
+// parsed when executing the group
+//
+// Group used to be a slice of pairs, it is a struct since
+// it holds the group's settings, use NewGroup instead of a
+// composite literal, Len instead of len and Pairs instead
+// of indexing and ranging over the group
+type Group struct {
+	pairs      []Pair
+	registry   Registry
+	out        io.Writer
+	renderer   Renderer
+	renderers  map[string]Renderer
+	outputFlag bool
+	errOut     io.Writer
+	exitCodes  []exitCode
+	recovery   bool
 
-// Add a command and an action function to the group
+	suggestDistance int
+	abbreviations   bool
+
+	index *index
+
+	defaultFn  func(ctx context.Context) error
+	fallbackFn func(ctx context.Context, args []string, err *NoMatchError) error
+	plugins    *Plugins
+	aliases    map[string]Alias
+	features   []string
+
+	interceptors interceptors
+}
+
+// builtin renderers by their names
+var renderers = map[string]Renderer{
+	"json":  &JSONRenderer{Indent: "  "},
+	"text":  new(TextRenderer),
+	"table": new(TableRenderer)}
+
+// NewGroup returns a group of the given pairs
+func NewGroup(pairs ...Pair) *Group {
+	g := new(Group)
+
+	for _, p := range pairs {
+		g.Add(p.Cmd, p.Fn)
+	}
+
+	return g
+}
+
+// Add a command and an action function to the group, the action
+// function is not checked against the command (see AddChecked)
 func (g *Group) Add(cmd Command, fn interface{}) *Group {
-	*g = append(*g, Pair{cmd, fn})
+	if g.index == nil {
+		g.index = new(index)
+	}
+
+	g.index.insert(len(g.pairs), cmd)
+	g.pairs = append(g.pairs, Pair{cmd, fn})
+	return g
+}
+
+// AddChecked adds a command and an action function to the group,
+// like Add, only if the action function is compatible with the
+// command's arguments and with the values provided to the group and
+// to the command (see Compiler.CompileFor and Group.Provide)
+func (g *Group) AddChecked(cmd Command, fn interface{}) error {
+	if c, ok := cmd.(*command); ok {
+		if err := c.checkFunc(fn, &binder{cmd: c, registries: []*Registry{&c.registry, &g.registry}}); err != nil {
+			return err
+		}
+	}
+
+	g.Add(cmd, fn)
+
+	return nil
+}
+
+// Len returns the number of commands in the group
+func (g *Group) Len() int {
+	return len(g.pairs)
+}
+
+// Pairs returns the group's commands and their action functions
+func (g *Group) Pairs() []Pair {
+	return append([]Pair(nil), g.pairs...)
+}
+
+// Provide registers values that are injected into the action
+// functions of the group's commands (see Registry.Provide),
+// values should be provided before adding commands for their
+// action functions to be checked against the provided types
+func (g *Group) Provide(values ...interface{}) error {
+	return g.registry.Provide(values...)
+}
+
+// SetOutput sets the writer of the rendered outputs of the action
+// functions (os.Stdout by default), the outputs of a group are
+// discarded unless SetOutput, SetRenderer or EnableOutputFlag is
+// called, or the group is executed with a context from WithOutput
+func (g *Group) SetOutput(w io.Writer) *Group {
+	g.out = w
+	return g
+}
+
+// SetRenderer sets the renderer of the outputs of the
+// action functions (a TextRenderer by default)
+func (g *Group) SetRenderer(r Renderer) *Group {
+	g.renderer = r
+	return g
+}
+
+// RegisterRenderer registers a renderer by name for the output
+// flag, the builtin renderers are "json", "text" and "table"
+func (g *Group) RegisterRenderer(name string, r Renderer) *Group {
+	if g.renderers == nil {
+		g.renderers = make(map[string]Renderer)
+	}
+
+	g.renderers[name] = r
+	return g
+}
+
+// Renderer returns the renderer that is registered by
+// name, or nil if no such renderer exists
+func (g *Group) Renderer(name string) Renderer {
+	if r, found := g.renderers[name]; found {
+		return r
+	}
+
+	return renderers[name]
+}
+
+// EnableOutputFlag makes the group accept "--output name" (or
+// "--output=name") at the flag position of the arguments, right after
+// the leading constants of the group's commands (and only there, after
+// a command's own flags or variables it is an unknown argument), the
+// flag is removed before testing the commands, the outputs are rendered
+// by the named renderer and the group's usage text describes the flag
+func (g *Group) EnableOutputFlag() *Group {
+	g.outputFlag = true
+	return g
+}
+
+// EnableRecovery makes the group recover panics of the action
+// functions, a panic fails its command with a *PanicError and
+// the next commands are tested as usual (see WithRecovery)
+func (g *Group) EnableRecovery() *Group {
+	g.recovery = true
 	return g
 }
 
+// Use adds middleware around the action functions of the group's
+// commands, before the commands middleware (see Middleware), the
+// first middleware is the outermost, a nil middleware panics
+func (g *Group) Use(mw ...Middleware) *Group {
+	for _, m := range mw {
+		if m == nil {
+			panic("funcv: middleware is nil")
+		}
+	}
+
+	g.interceptors.middleware = append(g.interceptors.middleware, mw...)
+	return g
+}
+
+// AddHooks adds hooks around the execution
+// of the group's commands (see Hooks)
+func (g *Group) AddHooks(hooks Hooks) *Group {
+	g.interceptors.hooks = append(g.interceptors.hooks, hooks)
+	return g
+}
+
+// prepare returns the execution settings (of ctx with the group's
+// registry, output, recovery, features and interceptors) and the
+// arguments for executing the group's commands, with the aliases
+// and the abbreviations expanded
+func (g *Group) prepare(ctx context.Context, args []string) (*execution, []string, error) {
+	e := executionOf(ctx)
+	e.registry, e.interceptors = &g.registry, &g.interceptors
+
+	if len(g.features) > 0 {
+		e.features = e.enable(g.features...)
+	}
+
+	if g.errOut != nil {
+		e.warnings = g.errOut
+	}
+
+	if g.recovery {
+		e.recovery = true
+	}
+
+	var err error
+
+	if args, err = g.unalias(args); err != nil {
+		return e, args, err
+	}
+
+	if g.abbreviations {
+		if args, err = g.expand(args); err != nil {
+			return e, args, err
+		}
+	}
+
+	o := output{w: g.out, r: g.renderer}
+	prev := e.output
+
+	if prev != nil {
+		if o.w == nil {
+			o.w = prev.w
+		}
+
+		if o.r == nil {
+			o.r = prev.r
+		}
+	}
+
+	if g.outputFlag {
+		var r Renderer
+
+		if r, args, err = g.extractOutput(args); err != nil {
+			return e, args, err
+		}
+
+		if r != nil {
+			o.r = r
+		}
+	}
+
+	if prev == nil && g.out == nil && g.renderer == nil && !g.outputFlag {
+		return e, args, nil
+	}
+
+	if o.w == nil {
+		o.w = os.Stdout
+	}
+
+	if o.r == nil {
+		o.r = new(TextRenderer)
+	}
+
+	e.output = &o
+
+	return e, args, nil
+}
+
+// start prepares the arguments for executing the group's commands
+// (see prepare) and returns the execution settings, the diagnostics
+// of the prepared arguments and the candidates (see candidates), if
+// preparing fails there are no candidates and the diagnostics report
+// the error
+func (g *Group) start(ctx context.Context, args []string) (*execution, *diagnostics, []int) {
+	e, rest, err := g.prepare(ctx, args)
+
+	if err != nil {
+		return e, &diagnostics{args: args, group: g, cause: err}, nil
+	}
+
+	return e, &diagnostics{args: rest, group: g}, g.candidates(rest)
+}
+
+// extractOutput returns the renderer that the output flag at the
+// flag position of the arguments (after the leading constants of the
+// group's commands) names, and the arguments without the flag, the
+// renderer is nil if there is no output flag at the flag position
+func (g *Group) extractOutput(args []string) (Renderer, []string, error) {
+	var d int
+
+	if g.index != nil {
+		d, _ = g.index.deepest(args)
+	}
+
+	if d == len(args) {
+		return nil, args, nil
+	}
+
+	var name string
+
+	n := 1
+
+	switch token := args[d]; {
+	case token == "--output":
+		if d+1 == len(args) {
+			return nil, args, fmt.Errorf("funcv: missing value for --output")
+		}
+
+		name, n = args[d+1], 2
+	case strings.HasPrefix(token, "--output="):
+		name = strings.TrimPrefix(token, "--output=")
+	default:
+		return nil, args, nil
+	}
+
+	r := g.Renderer(name)
+
+	if r == nil {
+		return nil, args, fmt.Errorf("funcv: unknown output %q", name)
+	}
+
+	return r, append(append([]string(nil), args[:d]...), args[d+n:]...), nil
+}
+
+// writeOutput writes the usage text of the output flag
+func (g *Group) writeOutput(w io.Writer) (int64, error) {
+	var names []string
+
+	for name := range renderers {
+		if _, found := g.renderers[name]; !found {
+			names = append(names, name)
+		}
+	}
+
+	for name := range g.renderers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	n, err := fmt.Fprintf(w, "output:\n\t--output name\trender the outputs by %s, right after the command's constants", strings.Join(names, "|"))
+	return int64(n), err
+}
+
 // Call the i's action function with the given parameters
 // invalid input will panic
 func (g *Group) Call(i int, params ...interface{}) {
@@ -30,60 +354,223 @@ func (g *Group) Call(i int, params ...interface{}) {
 		in = append(in, reflect.ValueOf(param))
 	}
 
-	reflect.ValueOf((*g)[i].Fn).Call(in)
+	reflect.ValueOf(g.pairs[i].Fn).Call(in)
 }
 
 // ExecuteAll tests the supplied arguments against all commands
 // in the group, if a suitable command found, the paired action
 // function is called with the extracted parameters, the number of
-// called functions is returned
+// called functions is returned, if no command matches, the default
+// or the fallback action is called instead (see SetDefault and
+// SetFallback) and counts as a called function if it succeeds
 func (g *Group) ExecuteAll(args []string) (n int) {
-	for _, p := range *g {
-		if _, err := p.Cmd.Execute(args, p.Fn); err == nil {
+	return g.ExecuteAllContext(context.Background(), args)
+}
+
+// ExecuteAllContext is the same as ExecuteAll but with a context
+// that is passed to the action functions (see Command.ExecuteContext),
+// no more commands are tested after ctx is done, the outputs of the
+// action functions are rendered by the group's renderer
+func (g *Group) ExecuteAllContext(ctx context.Context, args []string) (n int) {
+	n, _ = g.executeAll(ctx, args, false)
+	return
+}
+
+// ExecuteAllErr is the same as ExecuteAll but also returns the errors
+// of the action functions that failed (ex: a *PanicError, see
+// EnableRecovery) joined together, the error of the default or the
+// fallback action or, if there is none, a *NoMatchError
+func (g *Group) ExecuteAllErr(args []string) (int, error) {
+	return g.ExecuteAllErrContext(context.Background(), args)
+}
+
+// ExecuteAllErrContext is the same as ExecuteAllErr but with
+// a context (see ExecuteAllContext)
+func (g *Group) ExecuteAllErrContext(ctx context.Context, args []string) (int, error) {
+	return g.executeAll(ctx, args, true)
+}
+
+// executeAll executes all the matching commands, the *NoMatchError
+// is built only if report is true (see Group.unmatched)
+func (g *Group) executeAll(ctx context.Context, args []string, report bool) (n int, err error) {
+	var errs []error
+
+	matched := false
+	e, diag, candidates := g.start(ctx, args)
+
+	for _, i := range candidates {
+		p := g.pairs[i]
+
+		if err := ctx.Err(); err != nil {
+			return n, errors.Join(append(errs, err)...)
+		}
+
+		res, err := e.parse(ctx, p.Cmd, diag.args)
+
+		if err != nil {
+			diag.add(i, p.Cmd, res, err)
+			continue
+		}
+
+		matched = true
+
+		if err := res.execute(ctx, e, p.Fn); err == nil {
 			n++
+		} else {
+			errs = append(errs, err)
 		}
 	}
 
-	return
+	if !matched {
+		if handled, err := g.unmatched(ctx, e, diag, report); !handled || err != nil {
+			return n, err
+		}
+
+		return n + 1, nil
+	}
+
+	return n, errors.Join(errs...)
 }
 
 // ExecuteFirst tests the supplied arguments against the commands
 // in the group, if a suitable command found, the paired action
 // function is called with the extracted parameters and the method
 // returns immediately the command's index, without testing other
-// commands, if no suitable command found, the method returns a
-// negative value
+// commands (also if the action function fails), if no suitable
+// command found, the method returns a
+// negative value, IndexHandled if the default or the fallback action
+// (see SetDefault and SetFallback) succeeded and IndexNotFound otherwise
 func (g *Group) ExecuteFirst(args []string) (i int) {
-	var p Pair
+	return g.ExecuteFirstContext(context.Background(), args)
+}
+
+// ExecuteFirstContext is the same as ExecuteFirst but with a context
+// that is passed to the action functions (see Command.ExecuteContext),
+// no more commands are tested after ctx is done, the outputs of the
+// action functions are rendered by the group's renderer
+func (g *Group) ExecuteFirstContext(ctx context.Context, args []string) (i int) {
+	i, _ = g.executeFirst(ctx, args, false)
+	return
+}
+
+// ExecuteFirstErr is the same as ExecuteFirst but also returns the
+// error of the executed command's action function (ex: a *PanicError,
+// see EnableRecovery) with the command's index or, with a negative
+// index, the error of the default or the fallback action or, if
+// there is none, a *NoMatchError
+func (g *Group) ExecuteFirstErr(args []string) (int, error) {
+	return g.ExecuteFirstErrContext(context.Background(), args)
+}
+
+// ExecuteFirstErrContext is the same as ExecuteFirstErr but
+// with a context (see ExecuteFirstContext)
+func (g *Group) ExecuteFirstErrContext(ctx context.Context, args []string) (int, error) {
+	return g.executeFirst(ctx, args, true)
+}
+
+// executeFirst executes the first matching command, the
+// *NoMatchError is built only if report is true (see Group.unmatched)
+func (g *Group) executeFirst(ctx context.Context, args []string, report bool) (int, error) {
+	e, diag, candidates := g.start(ctx, args)
 
-	for i, p = range *g {
-		if _, err := p.Cmd.Execute(args, p.Fn); err == nil {
-			return
+	for _, i := range candidates {
+		p := g.pairs[i]
+
+		if err := ctx.Err(); err != nil {
+			return -1, err
+		}
+
+		res, err := e.parse(ctx, p.Cmd, diag.args)
+
+		if err != nil {
+			diag.add(i, p.Cmd, res, err)
+			continue
 		}
+
+		return i, res.execute(ctx, e, p.Fn)
+	}
+
+	if _, err := g.unmatched(ctx, e, diag, report); err != nil {
+		return IndexNotFound, err
+	}
+
+	return IndexHandled, nil
+}
+
+// SignalContext returns a copy of the parent context that is canceled
+// when one of the given signals arrives (os.Interrupt and SIGTERM if
+// none are given) or when the returned stop function is called
+func SignalContext(parent context.Context, sigs ...os.Signal) (context.Context, context.CancelFunc) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
 	}
 
-	return -1
+	return signal.NotifyContext(parent, sigs...)
 }
 
-// WriteTo will write to the writer an informative usage
-// text about the commands in the group
+// WriteTo will write to the writer an informative usage text about
+// the commands in the group, its aliases and its plugins, the plugins
+// are listed only if their Path or Allow is set (see Plugins)
 func (g *Group) WriteTo(w io.Writer) (int64, error) {
 	var written int64
+	var shown int
+
+	for _, p := range g.pairs {
+		if isHidden(p.Cmd) {
+			continue
+		}
+
+		if shown > 0 {
+			if n, err := fmt.Fprint(w, "\n\n"); err == nil {
+				written += int64(n)
+			} else {
+				return written + int64(n), err
+			}
+		}
 
-	for i, p := range *g {
 		if n, err := p.Cmd.WriteTo(w); err == nil {
 			written += n
 		} else {
 			return written + n, err
 		}
 
-		if i+1 < len(*g) {
+		shown++
+	}
+
+	var sections []func(w io.Writer) (int64, error)
+
+	if g.outputFlag {
+		sections = append(sections, g.writeOutput)
+	}
+
+	if aliases := g.Aliases(); len(aliases) > 0 {
+		sections = append(sections, func(w io.Writer) (int64, error) {
+			return g.writeAliases(w, aliases)
+		})
+	}
+
+	if g.plugins.listed() {
+		if names := g.Plugins(); len(names) > 0 {
+			sections = append(sections, func(w io.Writer) (int64, error) {
+				return g.writePlugins(w, names)
+			})
+		}
+	}
+
+	for i, section := range sections {
+		if i > 0 || shown > 0 {
 			if n, err := fmt.Fprint(w, "\n\n"); err == nil {
 				written += int64(n)
 			} else {
 				return written + int64(n), err
 			}
 		}
+
+		if n, err := section(w); err == nil {
+			written += n
+		} else {
+			return written + n, err
+		}
 	}
 
 	return written, nil
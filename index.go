@@ -0,0 +1,111 @@
+package funcv
+
+import (
+	"sort"
+	"strings"
+)
+
+// index of a group's commands by their leading constants, a trie
+// keyed by constant tokens, case-insensitive constants are keyed
+// by their lower case text
+type index struct {
+	sensitive   map[string]*index
+	insensitive map[string]*index
+	cmds        []int // the commands whose leading constants end here
+}
+
+// insert the command of the given index by its leading constants
+func (x *index) insert(i int, cmd Command) {
+	node := x
+
+	for _, c := range leadingConstants(cmd) {
+		children := &node.sensitive
+		key := c.text
+
+		if c.insensitive {
+			children = &node.insensitive
+			key = strings.ToLower(c.text)
+		}
+
+		if *children == nil {
+			*children = make(map[string]*index)
+		}
+
+		next, found := (*children)[key]
+
+		if !found {
+			next = new(index)
+			(*children)[key] = next
+		}
+
+		node = next
+	}
+
+	node.cmds = append(node.cmds, i)
+}
+
+// candidates returns the indexes, by their order, of the commands
+// whose leading constants match the start of the arguments
+func (x *index) candidates(args []string) []int {
+	var found []int
+
+	x.collect(args, &found)
+
+	sort.Ints(found)
+
+	return found
+}
+
+func (x *index) collect(args []string, found *[]int) {
+	*found = append(*found, x.cmds...)
+
+	if len(args) == 0 {
+		return
+	}
+
+	if next, ok := x.sensitive[args[0]]; ok {
+		next.collect(args[1:], found)
+	}
+
+	if next, ok := x.insensitive[strings.ToLower(args[0])]; ok {
+		next.collect(args[1:], found)
+	}
+}
+
+// deepest returns the number of leading arguments that match the
+// leading constants of some indexed command and the node they lead to
+func (x *index) deepest(args []string) (int, *index) {
+	d, node := 0, x
+
+	if len(args) == 0 {
+		return d, node
+	}
+
+	if next, ok := x.sensitive[args[0]]; ok {
+		d, node = next.deepest(args[1:])
+		d++
+	}
+
+	if next, ok := x.insensitive[strings.ToLower(args[0])]; ok {
+		if nd, nnode := next.deepest(args[1:]); nd+1 > d {
+			d, node = nd+1, nnode
+		}
+	}
+
+	return d, node
+}
+
+// leaf returns true if no constant follows the node
+func (x *index) leaf() bool {
+	return len(x.sensitive) == 0 && len(x.insensitive) == 0
+}
+
+// candidates returns the indexes, by their order, of the group's
+// commands that can match the arguments by their leading constants
+func (g *Group) candidates(args []string) []int {
+	if g.index == nil {
+		return nil
+	}
+
+	return g.index.candidates(args)
+}
@@ -0,0 +1,76 @@
+package funcv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	var grp Group
+
+	cmds := []Command{
+		NewCommand("").AddConstant("tool", false).AddConstant("add", false).MustCompile(),
+		NewCommand("").AddVariable("v", "", new(StringConverter)).MustCompile(),
+		NewCommand("").AddConstant("tool", false).AddConstant("ADD", true).MustCompile(),
+		NewCommand("").AddConstant("tool", false).AddVariable("v", "", new(StringConverter)).MustCompile(),
+		NewCommand("").AddConstant("tool", false).AddConstant("remove", false).MustCompile(),
+		NewCommand("").AddConstant("other", false).MustCompile(),
+	}
+
+	for _, cmd := range cmds {
+		grp.Add(cmd, nil)
+	}
+
+	cases := map[string][]int{
+		"tool add":    {0, 1, 2, 3},
+		"tool Add":    {1, 2, 3},
+		"tool remove": {1, 3, 4},
+		"tool":        {1, 3},
+		"other":       {1, 5},
+		"":            {1},
+	}
+
+	for args, expected := range cases {
+		if found := grp.candidates(strings.Fields(args)); !reflect.DeepEqual(found, expected) {
+			t.Fatal(args, found)
+		}
+	}
+
+	if i := grp.ExecuteFirst([]string{"tool", "ADD"}); i != 2 {
+		t.Fatal(i)
+	}
+}
+
+func benchmarkGroup(size int) *Group {
+	grp := new(Group)
+
+	for i := 0; i < size; i++ {
+		if err := NewCommand("").
+			AddConstant("admin", false).
+			AddConstant(fmt.Sprintf("cmd%d", i), false).
+			AddFlag("level", "", new(IntegerConverter), 0).
+			AddVariable("name", "", new(StringConverter)).
+			ToGroup(grp, func(level int, name string) {}); err != nil {
+			panic(err)
+		}
+	}
+
+	return grp
+}
+
+func BenchmarkExecuteFirst(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		grp := benchmarkGroup(size)
+		args := []string{"admin", fmt.Sprintf("cmd%d", size-1), "--level", "3", "name"}
+
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if grp.ExecuteFirst(args) < 0 {
+					b.FailNow()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package funcv
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var commandType = reflect.TypeOf((*Command)(nil)).Elem()
+
+// Registry of values that are injected into action function
+// parameters by their types, the zero value is an empty registry
+// ready to use
+type Registry struct {
+	values map[reflect.Type]reflect.Value
+}
+
+// Provide registers the given values by their types, an action
+// function parameter of a registered type (or of an interface type
+// that exactly one registered type implements) receives the value
+// instead of one of the command's parameters, providing two values
+// of the same type fails, and so does providing a value of a builtin
+// type (ex: string, []int or *bool) that parameters bound from the
+// arguments use, define a named type for such values instead
+func (r *Registry) Provide(values ...interface{}) error {
+	for _, v := range values {
+		if v == nil {
+			return fmt.Errorf("funcv: can't provide nil")
+		}
+
+		t := reflect.TypeOf(v)
+
+		if t == contextType || t == commandType || t == flagsType {
+			return fmt.Errorf("funcv: can't provide %v", t)
+		}
+
+		if isBuiltin(t) {
+			return fmt.Errorf("funcv: can't provide builtin type %v, use a named type", t)
+		}
+
+		if _, found := r.values[t]; found {
+			return fmt.Errorf("funcv: %v is already provided", t)
+		}
+
+		if r.values == nil {
+			r.values = make(map[reflect.Type]reflect.Value)
+		}
+
+		r.values[t] = reflect.ValueOf(v)
+	}
+
+	return nil
+}
+
+// isBuiltin returns true for the predeclared types and for the
+// unnamed types that are made of them (ex: []string or *int)
+func isBuiltin(t reflect.Type) bool {
+	for t.Name() == "" && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+
+	return t.PkgPath() == ""
+}
+
+// clone returns a copy of the registry
+func (r *Registry) clone() Registry {
+	var cp Registry
+
+	for t, v := range r.values {
+		if cp.values == nil {
+			cp.values = make(map[reflect.Type]reflect.Value, len(r.values))
+		}
+
+		cp.values[t] = v
+	}
+
+	return cp
+}
+
+// lookup returns the value that is injected into parameters of the
+// given type, found is false if there is no such value and err is
+// non-nil if more than one value is suitable
+func (r *Registry) lookup(t reflect.Type) (v reflect.Value, found bool, err error) {
+	if r == nil {
+		return v, false, nil
+	}
+
+	if v, found := r.values[t]; found {
+		return v, true, nil
+	}
+
+	if t.Kind() != reflect.Interface || t.NumMethod() == 0 {
+		return v, false, nil
+	}
+
+	var candidates []string
+
+	for vt, value := range r.values {
+		if vt.Implements(t) {
+			candidates = append(candidates, vt.String())
+			v = value
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return v, false, nil
+	case 1:
+		return v, true, nil
+	}
+
+	sort.Strings(candidates)
+
+	return reflect.Value{}, false, fmt.Errorf("funcv: ambiguous providers for %v [%s]", t, strings.Join(candidates, ", "))
+}
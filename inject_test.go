@@ -0,0 +1,107 @@
+package funcv
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+type logger struct {
+	lines []string
+}
+
+func (l *logger) Log(s string) {
+	l.lines = append(l.lines, s)
+}
+
+func TestGroupProvide(t *testing.T) {
+	var grp Group
+	var out bytes.Buffer
+
+	log := new(logger)
+
+	if err := grp.Provide(log, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewCommand("say something").
+		AddConstant("say", false).
+		AddVariable("text", "", new(StringConverter)).
+		ToGroup(&grp, func(l *logger, text string, w io.Writer, cmd Command) {
+			l.Log(text)
+			io.WriteString(w, text)
+
+			if cmd == nil {
+				t.Fatal("nil command")
+			}
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"say", "hello"}) != 0 {
+		t.FailNow()
+	}
+
+	if len(log.lines) != 1 || log.lines[0] != "hello" || out.String() != "hello" {
+		t.Fatal(log.lines, out.String())
+	}
+}
+
+func TestCommandProvide(t *testing.T) {
+	var out strings.Builder
+
+	c := NewCommand("").AddConstant("test", false).Provide(&out).MustCompile()
+
+	if _, err := c.Execute([]string{"test"}, func(w io.Writer) {
+		io.WriteString(w, "done")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "done" {
+		t.Fatal(out.String())
+	}
+}
+
+func TestProvideErrors(t *testing.T) {
+	var grp Group
+
+	if err := grp.Provide(new(logger), new(logger)); err == nil {
+		t.FailNow()
+	}
+
+	if err := grp.Provide(nil); err == nil {
+		t.FailNow()
+	}
+
+	for _, v := range []interface{}{"token", 1, []string{"a"}, new(bool), map[string]int{}} {
+		if err := grp.Provide(v); err == nil || !strings.Contains(err.Error(), "builtin") {
+			t.Fatal(v, err)
+		}
+	}
+
+	type token string
+
+	if err := grp.Provide(token("secret"), []*logger{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grp.Provide(new(bytes.Buffer), new(strings.Builder)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewCommand("").AddConstant("test", false).ToGroup(&grp, func(w io.Writer) {})
+
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatal(err)
+	}
+
+	err = NewCommand("").AddVariable("v", "", new(StringConverter)).ToGroup(&grp, func(r io.ReadCloser) {})
+
+	if err == nil || !strings.Contains(err.Error(), "no provider") {
+		t.Fatal(err)
+	}
+}
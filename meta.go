@@ -0,0 +1,288 @@
+package funcv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// metadata of a command or a flag
+type metadata struct {
+	hidden       bool
+	deprecated   bool
+	replacement  string
+	experimental string
+}
+
+// notes returns the usage notes of the metadata
+func (m metadata) notes() string {
+	var notes []string
+
+	if m.deprecated {
+		if m.replacement != "" {
+			notes = append(notes, fmt.Sprintf("deprecated, use %s", m.replacement))
+		} else {
+			notes = append(notes, "deprecated")
+		}
+	}
+
+	if m.experimental != "" {
+		notes = append(notes, fmt.Sprintf("experimental: %s", m.experimental))
+	}
+
+	if len(notes) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(notes, "; ") + "]"
+}
+
+func (c *command) Hide() Compiler {
+	c.meta.hidden = true
+	return c
+}
+
+func (c *command) Deprecate(replacement string) Compiler {
+	c.meta.deprecated, c.meta.replacement = true, replacement
+	return c
+}
+
+func (c *command) Experimental(feature string) Compiler {
+	if c.err != nil {
+		return c
+	}
+
+	if feature == "" {
+		c.err = fmt.Errorf("funcv: empty experimental feature")
+		return c
+	}
+
+	c.meta.experimental = feature
+	return c
+}
+
+func (c *command) HideFlag(name string) Builder {
+	return c.flagMeta(name, func(m *metadata) {
+		m.hidden = true
+	})
+}
+
+func (c *command) DeprecateFlag(name, replacement string) Builder {
+	return c.flagMeta(name, func(m *metadata) {
+		m.deprecated, m.replacement = true, replacement
+	})
+}
+
+func (c *command) ExperimentalFlag(name, feature string) Builder {
+	if c.err == nil && feature == "" {
+		c.err = fmt.Errorf("funcv: empty experimental feature for flag %s", name)
+	}
+
+	return c.flagMeta(name, func(m *metadata) {
+		m.experimental = feature
+	})
+}
+
+// flagMeta sets the metadata of an added flag
+func (c *command) flagMeta(name string, set func(m *metadata)) Builder {
+	if c.err != nil {
+		return c
+	}
+
+	for _, arg := range c.args {
+		if fb, ok := arg.(*flagsBuilder); ok {
+			if _, found := fb.converters[name]; found {
+				fb.setMeta(name, set)
+				return c
+			}
+		}
+	}
+
+	c.err = fmt.Errorf("funcv: flag %s not found", name)
+	return c
+}
+
+func (b *flagsBuilder) setMeta(name string, set func(m *metadata)) {
+	if b.meta == nil {
+		b.meta = make(map[string]metadata)
+	}
+
+	m := b.meta[name]
+	set(&m)
+	b.meta[name] = m
+}
+
+func (b *flagsBuilder) Hide() Compiler {
+	b.close()
+	return b.command.Hide()
+}
+
+func (b *flagsBuilder) Deprecate(replacement string) Compiler {
+	b.close()
+	return b.command.Deprecate(replacement)
+}
+
+func (b *flagsBuilder) Experimental(feature string) Compiler {
+	b.close()
+	return b.command.Experimental(feature)
+}
+
+func (b *flagsBuilder) HideFlag(name string) Builder {
+	return b.flagMeta(name, func(m *metadata) {
+		m.hidden = true
+	})
+}
+
+func (b *flagsBuilder) DeprecateFlag(name, replacement string) Builder {
+	return b.flagMeta(name, func(m *metadata) {
+		m.deprecated, m.replacement = true, replacement
+	})
+}
+
+func (b *flagsBuilder) ExperimentalFlag(name, feature string) Builder {
+	if b.command.err == nil && feature == "" {
+		b.command.err = fmt.Errorf("funcv: empty experimental feature for flag %s", name)
+	}
+
+	return b.flagMeta(name, func(m *metadata) {
+		m.experimental = feature
+	})
+}
+
+// flagMeta sets the metadata of a flag of the builder
+// or of a flag that was added before the builder
+func (b *flagsBuilder) flagMeta(name string, set func(m *metadata)) Builder {
+	if b.command.err != nil {
+		return b
+	}
+
+	if _, found := b.converters[name]; !found {
+		b.command.flagMeta(name, set)
+		return b
+	}
+
+	b.setMeta(name, set)
+	return b
+}
+
+type featuresKey struct{}
+
+// WithExperimental returns a copy of the parent context with which
+// the commands and flags of the given experimental features, and
+// of the features enabled in the parent, can execute
+func WithExperimental(parent context.Context, features ...string) context.Context {
+	e := execution{features: featuresFrom(parent)}
+	return context.WithValue(parent, featuresKey{}, e.enable(features...))
+}
+
+func featuresFrom(ctx context.Context) map[string]bool {
+	enabled, _ := ctx.Value(featuresKey{}).(map[string]bool)
+	return enabled
+}
+
+// EnableExperimental enables the commands and flags of the given
+// experimental features in the group (see WithExperimental)
+func (g *Group) EnableExperimental(features ...string) *Group {
+	g.features = append(g.features, features...)
+	return g
+}
+
+// checkExperimental returns an error if the command, or one of the
+// flags that were found in the arguments, is experimental and its
+// feature isn't enabled
+func checkExperimental(enabled map[string]bool, cmd Command, res *Result) error {
+	c, ok := cmd.(*command)
+
+	if !ok {
+		return nil
+	}
+
+	if f := c.meta.experimental; f != "" && !enabled[f] {
+		return fmt.Errorf("funcv: command %q requires the experimental feature %s (%w)", c.desc, f, ErrExperimental)
+	}
+
+	for _, arg := range c.args {
+		fb, ok := arg.(*flagsBuilder)
+
+		if !ok {
+			continue
+		}
+
+		for _, name := range fb.flags {
+			if f := fb.meta[name].experimental; f != "" && res.Set[name] && !enabled[f] {
+				return fmt.Errorf("funcv: flag %s requires the experimental feature %s (%w)", toFlag(name), f, ErrExperimental)
+			}
+		}
+	}
+
+	return nil
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns a copy of the parent context with which
+// the deprecation warnings of the executed commands and flags are
+// written to w instead of os.Stderr (io.Discard silences them)
+func WithWarnings(parent context.Context, w io.Writer) context.Context {
+	return context.WithValue(parent, warningsKey{}, w)
+}
+
+func warningsFrom(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(warningsKey{}).(io.Writer); ok {
+		return w
+	}
+
+	return os.Stderr
+}
+
+// warn writes the deprecation warnings of the command
+// and of the flags that were found in the arguments
+func (r *Result) warn(w io.Writer) {
+	var warnings []string
+
+	if r.cmd == nil {
+		return
+	}
+
+	if m := r.cmd.meta; m.deprecated {
+		warnings = append(warnings, deprecation(fmt.Sprintf("command %q", r.cmd.desc), m.replacement))
+	}
+
+	for _, arg := range r.cmd.args {
+		fb, ok := arg.(*flagsBuilder)
+
+		if !ok {
+			continue
+		}
+
+		names := append([]string(nil), fb.flags...)
+		sort.Strings(names)
+
+		for _, name := range names {
+			if m := fb.meta[name]; m.deprecated && r.Set[name] {
+				warnings = append(warnings, deprecation("flag "+toFlag(name), m.replacement))
+			}
+		}
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintln(w, warning)
+	}
+}
+
+func deprecation(what, replacement string) string {
+	if replacement == "" {
+		return fmt.Sprintf("warning: %s is deprecated", what)
+	}
+
+	return fmt.Sprintf("warning: %s is deprecated, use %s instead", what, replacement)
+}
+
+// isHidden returns true if the command is hidden from usage texts
+func isHidden(cmd Command) bool {
+	c, ok := cmd.(*command)
+	return ok && c.meta.hidden
+}
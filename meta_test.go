@@ -0,0 +1,317 @@
+package funcv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHidden(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("list").AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("ls").AddConstant("ls", false).Hide().ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst([]string{"ls"}); i != 1 {
+		t.Fatal(i)
+	}
+}
+
+func TestHiddenWriteTo(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("ls").AddConstant("ls", false).Hide().ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("list").AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+
+	if _, err := grp.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if usage := sb.String(); strings.Contains(usage, "> ls") || strings.HasPrefix(usage, "\n") {
+		t.Fatal(usage)
+	}
+}
+
+func TestHiddenFlagWriteTo(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("sync").AddConstant("sync", false).AddParameterlessFlag("debug", "debug", new(BooleanConverter), true, false).HideFlag("debug").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+
+	if _, err := grp.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if usage := sb.String(); strings.Contains(usage, "--debug") || strings.Contains(usage, "sync \n") {
+		t.Fatal(usage)
+	}
+}
+
+func TestHiddenFlagNames(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("sync").AddConstant("sync", false).AddParameterlessFlag("debug", "debug", new(BooleanConverter), true, false).HideFlag("debug").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range grp.flagNames() {
+		if name == "debug" {
+			t.FailNow()
+		}
+	}
+}
+
+func TestHiddenNoMatch(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("list").AddConstant("list", false).ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("ls").AddConstant("ls", false).Hide().ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteBest([]string{"lx"})
+
+	var nm *NoMatchError
+
+	if !errors.As(err, &nm) {
+		t.Fatal(err)
+	}
+
+	for _, m := range nm.Candidates {
+		if m.Index == 1 {
+			t.Fatal(m)
+		}
+	}
+
+	for _, s := range nm.Suggestions {
+		for _, c := range s.Candidates {
+			if c == "ls" {
+				t.Fatal(s)
+			}
+		}
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	var warnings bytes.Buffer
+
+	grp := new(Group).SetErrorOutput(&warnings)
+
+	if err := NewCommand("remove").AddConstant("rm", false).Deprecate("delete").ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst([]string{"rm"}); i != 0 {
+		t.Fatal(i)
+	}
+
+	if s := warnings.String(); s != "warning: command \"remove\" is deprecated, use delete instead\n" {
+		t.Fatal(s)
+	}
+}
+
+func TestDeprecatedWithoutReplacement(t *testing.T) {
+	var warnings bytes.Buffer
+
+	cmd := NewCommand("old").AddConstant("old", false).Deprecate("").MustCompile()
+
+	if _, err := cmd.ExecuteContext(WithWarnings(context.Background(), &warnings), []string{"old"}, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := warnings.String(); s != "warning: command \"old\" is deprecated\n" {
+		t.Fatal(s)
+	}
+}
+
+func TestDeprecatedFlag(t *testing.T) {
+	var warnings bytes.Buffer
+
+	grp := new(Group).SetErrorOutput(&warnings)
+
+	if err := NewCommand("list").AddConstant("list", false).AddParameterlessFlag("every", "all", new(BooleanConverter), true, false).DeprecateFlag("every", "--all").AddParameterlessFlag("all", "all", new(BooleanConverter), true, false).ToGroup(grp, func(bool, bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst([]string{"list", "--every"}); i != 0 {
+		t.Fatal(i)
+	}
+
+	if s := warnings.String(); s != "warning: flag --every is deprecated, use --all instead\n" {
+		t.Fatal(s)
+	}
+}
+
+func TestDeprecatedFlagNotGiven(t *testing.T) {
+	var warnings bytes.Buffer
+
+	grp := new(Group).SetErrorOutput(&warnings)
+
+	if err := NewCommand("list").AddConstant("list", false).AddParameterlessFlag("every", "all", new(BooleanConverter), true, false).DeprecateFlag("every", "--all").AddParameterlessFlag("all", "all", new(BooleanConverter), true, false).ToGroup(grp, func(bool, bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst([]string{"list", "--all"}); i != 0 || warnings.Len() != 0 {
+		t.Fatal(i, warnings.String())
+	}
+}
+
+func TestDeprecatedWriteTo(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("remove").AddConstant("rm", false).Deprecate("delete").ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("list").AddConstant("list", false).AddParameterlessFlag("every", "all", new(BooleanConverter), true, false).DeprecateFlag("every", "--all").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+
+	if _, err := grp.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if usage := sb.String(); !strings.Contains(usage, "remove [deprecated, use delete]:") || !strings.Contains(usage, "[deprecated, use --all]") {
+		t.Fatal(usage)
+	}
+}
+
+func TestExperimental(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("sync").AddConstant("sync", false).Experimental("sync").ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteBest([]string{"sync"})
+
+	var nm *NoMatchError
+
+	if !errors.As(err, &nm) || len(nm.Candidates) != 1 || nm.Candidates[0].Reason != ReasonExperimental || !errors.Is(nm.Candidates[0].Err, ErrExperimental) {
+		t.Fatal(err)
+	}
+}
+
+func TestExperimentalContext(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("sync").AddConstant("sync", false).Experimental("sync").ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i, err := grp.ExecuteBestContext(WithExperimental(context.Background(), "sync"), []string{"sync"}); i != 0 || err != nil {
+		t.Fatal(i, err)
+	}
+}
+
+func TestExperimentalFlag(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("push").AddConstant("push", false).AddParameterlessFlag("force", "force", new(BooleanConverter), true, false).ExperimentalFlag("force", "force").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst([]string{"push", "--force"}); i >= 0 {
+		t.Fatal(i)
+	}
+}
+
+func TestExperimentalFlagNotGiven(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("push").AddConstant("push", false).AddParameterlessFlag("force", "force", new(BooleanConverter), true, false).ExperimentalFlag("force", "force").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := grp.ExecuteFirst([]string{"push"}); i != 0 {
+		t.Fatal(i)
+	}
+}
+
+func TestExperimentalFlagEnabled(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("push").AddConstant("push", false).AddParameterlessFlag("force", "force", new(BooleanConverter), true, false).ExperimentalFlag("force", "force").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	grp.EnableExperimental("force")
+
+	if i := grp.ExecuteFirst([]string{"push", "--force"}); i != 0 {
+		t.Fatal(i)
+	}
+}
+
+func TestExperimentalCommand(t *testing.T) {
+	cmd := NewCommand("").AddConstant("sync", false).Experimental("sync").MustCompile()
+
+	if _, err := cmd.Execute([]string{"sync"}, func() {}); !errors.Is(err, ErrExperimental) {
+		t.Fatal(err)
+	}
+}
+
+func TestExperimentalCommandContext(t *testing.T) {
+	cmd := NewCommand("").AddConstant("sync", false).Experimental("sync").MustCompile()
+
+	if _, err := cmd.ExecuteContext(WithExperimental(WithExperimental(context.Background(), "sync"), "other"), []string{"sync"}, func() {}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExperimentalWriteTo(t *testing.T) {
+	var grp Group
+
+	if err := NewCommand("sync").AddConstant("sync", false).Experimental("sync").ToGroup(&grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("push").AddConstant("push", false).AddParameterlessFlag("force", "force", new(BooleanConverter), true, false).ExperimentalFlag("force", "force").ToGroup(&grp, func(bool) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+
+	if _, err := grp.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if usage := sb.String(); !strings.Contains(usage, "sync [experimental: sync]:") || !strings.Contains(usage, "[experimental: force]") {
+		t.Fatal(usage)
+	}
+}
+
+func TestMetadataErrors(t *testing.T) {
+	if _, err := NewCommand("").AddConstant("x", false).HideFlag("missing").Compile(); err == nil {
+		t.FailNow()
+	}
+
+	if _, err := NewCommand("").AddConstant("x", false).Experimental("").Compile(); err == nil {
+		t.FailNow()
+	}
+
+	if _, err := NewCommand("").AddFlag("x", "", new(IntegerConverter), 0).ExperimentalFlag("x", "").Compile(); err == nil {
+		t.FailNow()
+	}
+}
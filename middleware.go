@@ -0,0 +1,163 @@
+package funcv
+
+import (
+	"context"
+)
+
+// Invocation of an action function
+type Invocation struct {
+	Command Command     // the executed command
+	Result  *Result     // the parsed arguments
+	Fn      interface{} // the action function
+}
+
+// Handler binds and calls the action function of an invocation
+// with ctx, the function's outputs are returned with its error
+type Handler func(ctx context.Context, inv *Invocation) ([]interface{}, error)
+
+// Middleware wraps a handler with another handler, that can act
+// before and after calling next, change the context, the outputs
+// and the error, or not call next at all
+type Middleware func(next Handler) Handler
+
+// Hooks are called around the execution of commands, a nil hook
+// is skipped, the Before hooks of a group are called before the
+// hooks of a command and the After hooks of a command are called
+// before the hooks of a group:
+//
+//	BeforeParse, parse, AfterParse,
+//	BeforeCall, middleware(action function), AfterCall
+//
+// A group parses only the commands whose leading constants match
+// the arguments (see Group), the parse hooks are not called for
+// the other commands, not even when no command matches
+type Hooks struct {
+	// BeforeParse is called before parsing the arguments against
+	// a command, an error fails the command, it isn't called for
+	// the commands of a group that can't match the arguments by
+	// their leading constants
+	BeforeParse func(ctx context.Context, cmd Command, args []string) error
+	// AfterParse is called after parsing the arguments against
+	// a command with the result and parsing error, it isn't called
+	// when BeforeParse isn't
+	AfterParse func(ctx context.Context, cmd Command, res *Result, err error)
+	// BeforeCall is called before the middleware and the action
+	// function are called, an error fails the command
+	BeforeCall func(ctx context.Context, inv *Invocation) error
+	// AfterCall is called after the middleware and the action
+	// function with their outputs and error
+	AfterCall func(ctx context.Context, inv *Invocation, outputs []interface{}, err error)
+	// OnNoMatch is called (only for a group's hooks) when no
+	// command in the group matches the arguments, with the
+	// mismatches of the closest commands
+	OnNoMatch func(ctx context.Context, err *NoMatchError)
+}
+
+// interceptors are the hooks and the middleware of a group or a command
+type interceptors struct {
+	hooks      []Hooks
+	middleware []Middleware
+}
+
+// chain returns the interceptors of the group (of the execution)
+// followed by the interceptors of the command
+func (e *execution) chain(cmd Command) *interceptors {
+	grp := e.interceptors
+
+	c, ok := cmd.(*command)
+
+	if !ok {
+		return grp
+	}
+
+	return &interceptors{
+		hooks:      append(append([]Hooks(nil), grp.hooks...), c.interceptors.hooks...),
+		middleware: append(append([]Middleware(nil), grp.middleware...), c.interceptors.middleware...)}
+}
+
+func (i *interceptors) clone() interceptors {
+	return interceptors{
+		hooks:      append([]Hooks(nil), i.hooks...),
+		middleware: append([]Middleware(nil), i.middleware...)}
+}
+
+// parse parses the arguments against the command between the
+// BeforeParse and AfterParse hooks, experimental commands and flags
+// fail unless their features are enabled (see WithExperimental)
+func (e *execution) parse(ctx context.Context, cmd Command, args []string) (*Result, error) {
+	hooks := e.chain(cmd).hooks
+
+	for _, h := range hooks {
+		if h.BeforeParse == nil {
+			continue
+		}
+
+		if err := h.BeforeParse(ctx, cmd, args); err != nil {
+			return &Result{Remaining: args, err: err}, err
+		}
+	}
+
+	res, err := cmd.Parse(args)
+
+	if res == nil {
+		res = &Result{Remaining: args}
+	}
+
+	if res.cmd == nil {
+		res.ext, res.args = cmd, args
+	}
+
+	if err == nil {
+		if err = checkExperimental(e.features, cmd, res); err != nil {
+			res.err = err
+		}
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if h := hooks[i]; h.AfterParse != nil {
+			h.AfterParse(ctx, cmd, res, err)
+		}
+	}
+
+	return res, err
+}
+
+// noMatch calls the OnNoMatch hooks of the group (of the execution)
+// with the no match error of the diagnostics
+func (e *execution) noMatch(ctx context.Context, diag *diagnostics) {
+	for _, h := range e.interceptors.hooks {
+		if h.OnNoMatch != nil {
+			h.OnNoMatch(ctx, diag.err())
+		}
+	}
+}
+
+// invoke calls the action function of the invocation through the
+// middleware between the BeforeCall and AfterCall hooks
+func (e *execution) invoke(ctx context.Context, inv *Invocation, h Handler) ([]interface{}, error) {
+	i := e.chain(inv.Command)
+
+	for _, hooks := range i.hooks {
+		if hooks.BeforeCall == nil {
+			continue
+		}
+
+		if err := hooks.BeforeCall(ctx, inv); err != nil {
+			return nil, err
+		}
+	}
+
+	for j := len(i.middleware) - 1; j >= 0; j-- {
+		h = i.middleware[j](h)
+	}
+
+	outputs, err := h(ctx, inv)
+
+	for j := len(i.hooks) - 1; j >= 0; j-- {
+		if hooks := i.hooks[j]; hooks.AfterCall != nil {
+			hooks.AfterCall(ctx, inv, outputs, err)
+		}
+	}
+
+	return outputs, err
+}
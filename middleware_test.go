@@ -0,0 +1,193 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func recordingHooks(name string, events *[]string) Hooks {
+	return Hooks{
+		BeforeParse: func(ctx context.Context, cmd Command, args []string) error {
+			*events = append(*events, name+" before parse")
+			return nil
+		},
+		AfterParse: func(ctx context.Context, cmd Command, res *Result, err error) {
+			*events = append(*events, name+" after parse")
+		},
+		BeforeCall: func(ctx context.Context, inv *Invocation) error {
+			*events = append(*events, name+" before call")
+			return nil
+		},
+		AfterCall: func(ctx context.Context, inv *Invocation, outputs []interface{}, err error) {
+			*events = append(*events, name+" after call")
+		},
+		OnNoMatch: func(ctx context.Context, err *NoMatchError) {
+			*events = append(*events, name+" no match")
+		}}
+}
+
+func recordingMiddleware(name string, events *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, inv *Invocation) ([]interface{}, error) {
+			*events = append(*events, name+" in")
+			outputs, err := next(ctx, inv)
+			*events = append(*events, name+" out")
+			return outputs, err
+		}
+	}
+}
+
+func TestHooksOrder(t *testing.T) {
+	var grp Group
+	var events []string
+
+	grp.AddHooks(recordingHooks("group", &events)).
+		Use(recordingMiddleware("group mw1", &events), recordingMiddleware("group mw2", &events))
+
+	if err := NewCommand("").
+		AddConstant("test", false).
+		Use(recordingMiddleware("cmd mw", &events)).
+		AddHooks(recordingHooks("cmd", &events)).
+		ToGroup(&grp, func() { events = append(events, "action") }); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"test"}) != 0 {
+		t.FailNow()
+	}
+
+	expected := []string{
+		"group before parse", "cmd before parse", "cmd after parse", "group after parse",
+		"group before call", "cmd before call",
+		"group mw1 in", "group mw2 in", "cmd mw in", "action", "cmd mw out", "group mw2 out", "group mw1 out",
+		"cmd after call", "group after call"}
+
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatal(events)
+	}
+
+	events = nil
+
+	if grp.ExecuteAll([]string{"other"}) != 0 {
+		t.FailNow()
+	}
+
+	// the command can't match "other" by its leading constant, it isn't parsed
+	expected = []string{"group no match"}
+
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatal(events)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	c := NewCommand("").
+		AddConstant("test", false).
+		AddVariable("v", "", new(IntegerConverter)).
+		Use(func(next Handler) Handler {
+			return func(ctx context.Context, inv *Invocation) ([]interface{}, error) {
+				if inv.Result.Values["v"] == int64(0) {
+					return nil, errDenied
+				}
+
+				outputs, err := next(ctx, inv)
+
+				return append(outputs, "mw"), err
+			}
+		}).
+		MustCompile()
+
+	var outputs []interface{}
+
+	grp := new(Group).AddHooks(Hooks{AfterCall: func(ctx context.Context, inv *Invocation, out []interface{}, err error) {
+		outputs = out
+	}})
+
+	grp.Add(c, func(v int) int { return v })
+
+	if grp.ExecuteFirst([]string{"test", "0"}) != 0 || outputs != nil {
+		t.Fatal(outputs)
+	}
+
+	if grp.ExecuteFirst([]string{"test", "3"}) != 0 || !reflect.DeepEqual(outputs, []interface{}{3, "mw"}) {
+		t.Fatal(outputs)
+	}
+
+	if _, err := c.Execute([]string{"test", "0"}, func(v int) {}); err != errDenied {
+		t.Fatal(err)
+	}
+}
+
+func TestHooksErrors(t *testing.T) {
+	errFail := errors.New("fail")
+
+	called := false
+
+	c := NewCommand("").AddConstant("test", false).AddHooks(Hooks{BeforeParse: func(ctx context.Context, cmd Command, args []string) error {
+		return errFail
+	}}).MustCompile()
+
+	if _, err := c.Execute([]string{"test"}, func() { called = true }); err != errFail || called {
+		t.Fatal(err)
+	}
+
+	c = NewCommand("").AddConstant("test", false).AddHooks(Hooks{BeforeCall: func(ctx context.Context, inv *Invocation) error {
+		return errFail
+	}}).MustCompile()
+
+	if _, err := c.Execute([]string{"test"}, func() { called = true }); err != errFail || called {
+		t.Fatal(err)
+	}
+
+	if _, err := NewCommand("").AddConstant("test", false).Use(nil).Compile(); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestGroupUseNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.FailNow()
+		}
+	}()
+
+	new(Group).Use(nil)
+}
+
+func TestNestedExecution(t *testing.T) {
+	var grp Group
+	var events, nestedEvents []string
+
+	grp.AddHooks(recordingHooks("group", &events)).EnableRecovery()
+
+	if err := grp.Provide(new(logger)); err != nil {
+		t.Fatal(err)
+	}
+
+	inner := NewCommand("inner").AddConstant("inner", false).MustCompile()
+
+	var nested error
+
+	if err := NewCommand("outer").AddConstant("outer", false).ToGroup(&grp, func(ctx context.Context) {
+		events = nil
+		_, nested = inner.ExecuteContext(ctx, []string{"inner"}, func(*logger) {})
+		nestedEvents = events
+		inner.ExecuteContext(ctx, []string{"inner"}, func() { panic("boom") })
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var pe *PanicError
+
+	if i, err := grp.ExecuteFirstErr([]string{"outer"}); i != 0 || !errors.As(err, &pe) || pe.Command != "outer" {
+		t.Fatal(i, err)
+	}
+
+	if nested == nil || len(nestedEvents) != 0 {
+		t.Fatal(nested, nestedEvents)
+	}
+}
@@ -0,0 +1,55 @@
+package funcv
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned instead of a panic of an action
+// function when recovery is enabled (see WithRecovery)
+type PanicError struct {
+	Value   interface{} // the value passed to panic
+	Stack   []byte      // the stack trace of the panic
+	Command string      // the description of the executed command
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("funcv: panic in command %q: %v", e.Command, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+type recoveryKey struct{}
+
+// WithRecovery returns a copy of the parent context with which
+// panics of executed action functions are recovered and returned
+// as a *PanicError (see Command.ExecuteContext)
+func WithRecovery(parent context.Context) context.Context {
+	return context.WithValue(parent, recoveryKey{}, true)
+}
+
+func recoveryFrom(ctx context.Context) bool {
+	enabled, _ := ctx.Value(recoveryKey{}).(bool)
+	return enabled
+}
+
+// call calls the binding, if recovery is true a panic
+// is returned as a *PanicError of the given command
+func (b *Binding) call(recovery bool, desc string) (outputs []interface{}, err error) {
+	if recovery {
+		defer b.recover(desc, &err)
+	}
+
+	return b.Call()
+}
+
+func (*Binding) recover(desc string, err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Value: v, Stack: debug.Stack(), Command: desc}
+	}
+}
@@ -0,0 +1,86 @@
+package funcv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecovery(t *testing.T) {
+	c := NewCommand("boom").AddConstant("test", false).MustCompile()
+
+	errBoom := errors.New("boom")
+
+	_, err := c.ExecuteContext(WithRecovery(context.Background()), []string{"test"}, func() { panic(errBoom) })
+
+	var pe *PanicError
+
+	if !errors.As(err, &pe) || pe.Command != "boom" || len(pe.Stack) == 0 || !errors.Is(err, errBoom) {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.FailNow()
+		}
+	}()
+
+	c.Execute([]string{"test"}, func() { panic("boom") })
+}
+
+func TestGroupRecovery(t *testing.T) {
+	var grp Group
+	var errOut bytes.Buffer
+
+	grp.EnableRecovery().SetErrorOutput(&errOut)
+
+	called := false
+
+	if err := NewCommand("panics").AddConstant("test", false).ToGroup(&grp, func() { panic("boom") }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("").AddConstant("test", false).ToGroup(&grp, func() { called = true }); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := grp.ExecuteAll([]string{"test"}); n != 1 || !called {
+		t.Fatal(n, called)
+	}
+
+	if grp.Main([]string{"test"}) != ExitFailure || !strings.Contains(errOut.String(), `panic in command "panics": boom`) {
+		t.Fatal(errOut.String())
+	}
+
+	var pe *PanicError
+
+	if n, err := grp.ExecuteAllErr([]string{"test"}); n != 1 || !errors.As(err, &pe) || pe.Command != "panics" {
+		t.Fatal(n, err)
+	}
+
+	if i, err := grp.ExecuteFirstErr([]string{"test"}); i != 0 || !errors.As(err, &pe) {
+		t.Fatal(i, err)
+	}
+}
+
+func TestGroupRecoveryFirst(t *testing.T) {
+	var grp Group
+
+	grp.EnableRecovery()
+
+	if err := NewCommand("panics").AddConstant("test", false).ToGroup(&grp, func() { panic("boom") }); err != nil {
+		t.Fatal(err)
+	}
+
+	var pe *PanicError
+
+	if i, err := grp.ExecuteFirstErr([]string{"test"}); i != 0 || !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Fatal(i, err)
+	}
+
+	if i, err := grp.ExecuteBest([]string{"test"}); i != 0 || !errors.As(err, &pe) {
+		t.Fatal(i, err)
+	}
+}
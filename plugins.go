@@ -0,0 +1,225 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Plugins configures the external subcommands of a group, when no
+// command matches the arguments, the first token that doesn't continue
+// the leading constants of the group's commands (ex: "foo" in "tool foo
+// x" if "tool" is a leading constant, unless no constant follows
+// "tool") names an executable, Prefix + name, that is searched for
+// in Path and run with the remaining arguments
+type Plugins struct {
+	Prefix string    // the executables name prefix (ex: "tool-", see EnablePlugins)
+	Path   []string  // the directories to search (PATH if empty)
+	Allow  []string  // the allowed plugin names (all if empty)
+	Stdin  io.Reader // the plugins stdin (os.Stdin if nil)
+	Stdout io.Writer // the plugins stdout (os.Stdout if nil)
+	Stderr io.Writer // the plugins stderr (os.Stderr if nil)
+}
+
+// PluginError is returned when a plugin exits with a non-zero
+// status, the status is the exit code (see ExitCoder)
+type PluginError struct {
+	Name   string // the plugin's name
+	Path   string // the plugin's executable
+	Status int    // the plugin's exit status
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("funcv: plugin %s exited with status %d", e.Name, e.Status)
+}
+
+// ExitCode returns the plugin's exit status
+func (e *PluginError) ExitCode() int {
+	return e.Status
+}
+
+// EnablePlugins makes the group run external subcommands (see
+// Plugins), plugins without a Prefix panic unless their Path or
+// Allow is set, so that a mistyped command doesn't run any
+// executable in PATH (ex: "tool rm -rf x" running rm)
+func (g *Group) EnablePlugins(p Plugins) *Group {
+	if p.Prefix == "" && len(p.Path) == 0 && len(p.Allow) == 0 {
+		panic("funcv: plugins without a prefix need a path or an allow list")
+	}
+
+	g.plugins = &p
+	return g
+}
+
+// DisablePlugins stops the group from running external subcommands
+func (g *Group) DisablePlugins() *Group {
+	g.plugins = nil
+	return g
+}
+
+func (p *Plugins) dirs() []string {
+	if len(p.Path) > 0 {
+		return p.Path
+	}
+
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+// listed returns true if the plugins are listed in usage texts,
+// only plugins with an explicit path or allow-list are listed, so
+// that writing the usage text doesn't search PATH for executables
+func (p *Plugins) listed() bool {
+	return p != nil && (len(p.Path) > 0 || len(p.Allow) > 0)
+}
+
+func (p *Plugins) allowed(name string) bool {
+	if len(p.Allow) == 0 {
+		return true
+	}
+
+	for _, a := range p.Allow {
+		if a == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookup returns the executable of the named plugin
+func (p *Plugins) lookup(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) || !p.allowed(name) {
+		return "", false
+	}
+
+	for _, dir := range p.dirs() {
+		path := filepath.Join(dir, p.Prefix+name)
+
+		if isExecutable(path) {
+			return path, true
+		}
+	}
+
+	return "", false
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// Plugins returns the names of the allowed plugins
+// that are found in the path, sorted
+func (g *Group) Plugins() []string {
+	if g.plugins == nil {
+		return nil
+	}
+
+	var names []string
+
+	seen := make(map[string]bool)
+
+	for _, dir := range g.plugins.dirs() {
+		entries, err := os.ReadDir(dir)
+
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := strings.TrimPrefix(entry.Name(), g.plugins.Prefix)
+
+			if name == entry.Name() && g.plugins.Prefix != "" || name == "" || seen[name] {
+				continue
+			}
+
+			if g.plugins.allowed(name) && isExecutable(filepath.Join(dir, entry.Name())) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// plugin runs the plugin that is named by the first token that doesn't
+// continue the leading constants of the group's commands, found is
+// false if there is no such plugin
+func (g *Group) plugin(ctx context.Context, args []string) (found bool, err error) {
+	if g.plugins == nil {
+		return false, nil
+	}
+
+	d := 0
+
+	if g.index != nil {
+		var node *index
+
+		if d, node = g.index.deepest(args); d > 0 && node.leaf() {
+			return false, nil
+		}
+	}
+
+	if d >= len(args) {
+		return false, nil
+	}
+
+	name := args[d]
+	path, found := g.plugins.lookup(name)
+
+	if !found {
+		return false, nil
+	}
+
+	cmd := exec.CommandContext(ctx, path, args[d+1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = g.plugins.Stdin, g.plugins.Stdout, g.plugins.Stderr
+
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	err = cmd.Run()
+
+	var ee *exec.ExitError
+
+	if errors.As(err, &ee) && ee.ExitCode() > 0 {
+		return true, &PluginError{Name: name, Path: path, Status: ee.ExitCode()}
+	}
+
+	if err != nil {
+		return true, fmt.Errorf("funcv: plugin %s (%w)", name, err)
+	}
+
+	return true, nil
+}
+
+// writePlugins will write to the writer the names of the group's plugins
+func (g *Group) writePlugins(w io.Writer, names []string) (int64, error) {
+	var sb strings.Builder
+
+	sb.WriteString("plugins:")
+
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\n\t%s\t(%s%s)", name, g.plugins.Prefix, name))
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
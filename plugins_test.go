@@ -0,0 +1,253 @@
+package funcv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePlugin(t *testing.T, dir, name, script string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `exit 0`)
+	writePlugin(t, dir, "tool-fail", `exit 3`)
+	writePlugin(t, dir, "other-x", `exit 0`)
+
+	if err := os.WriteFile(filepath.Join(dir, "tool-data"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}})
+
+	if names := grp.Plugins(); strings.Join(names, ",") != "fail,hello" {
+		t.Fatal(names)
+	}
+}
+
+func TestPluginsExecute(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `echo "hello $@"`)
+
+	var out bytes.Buffer
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}, Stdout: &out})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := grp.ExecuteAll([]string{"tool", "hello", "a", "b"}); n != 1 || out.String() != "hello a b\n" {
+		t.Fatal(n, out.String())
+	}
+}
+
+func TestPluginsWithoutProgramName(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `echo "hello $@"`)
+
+	var out bytes.Buffer
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}, Stdout: &out})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := grp.ExecuteAll([]string{"hello"}); n != 1 || out.String() != "hello \n" {
+		t.Fatal(n, out.String())
+	}
+}
+
+func TestPluginsExitStatus(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-fail", `echo "failed" >&2; exit 3`)
+
+	var out, errOut bytes.Buffer
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}, Stderr: &out}).SetErrorOutput(&errOut)
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := grp.Main([]string{"tool", "fail"}); code != 3 || out.String() != "failed\n" {
+		t.Fatal(code, out.String())
+	}
+}
+
+func TestPluginsError(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-fail", `exit 3`)
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteBest([]string{"tool", "fail"})
+
+	var pe *PluginError
+
+	if !errors.As(err, &pe) || pe.Name != "fail" || pe.Status != 3 {
+		t.Fatal(err)
+	}
+}
+
+func TestPluginsNotFound(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `exit 0`)
+
+	if err := os.WriteFile(filepath.Join(dir, "tool-data"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"tool", "data"}, {"tool", "x"}, {"tool", "version", "hello"}, {"tool", "../tool-hello"}} {
+		if _, err := grp.ExecuteBest(args); !errors.Is(err, ErrNoMatch) {
+			t.Fatal(args, err)
+		}
+	}
+}
+
+func TestPluginsAllowList(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `echo "hello"`)
+	writePlugin(t, dir, "tool-fail", `exit 3`)
+
+	var out bytes.Buffer
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}, Allow: []string{"fail"}, Stdout: &out})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := grp.Plugins(); len(names) != 1 || names[0] != "fail" {
+		t.Fatal(names)
+	}
+
+	if grp.ExecuteAll([]string{"tool", "hello"}) != 0 || out.Len() != 0 {
+		t.Fatal(out.String())
+	}
+}
+
+func TestPluginsDisabled(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `echo "hello"`)
+
+	var out bytes.Buffer
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}, Stdout: &out}).DisablePlugins()
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.Plugins() != nil || grp.ExecuteAll([]string{"tool", "hello"}) != 0 || out.Len() != 0 {
+		t.Fatal(out.String())
+	}
+}
+
+func TestPluginsWriteTo(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `exit 0`)
+	writePlugin(t, dir, "tool-fail", `exit 3`)
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Path: []string{dir}})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := grp.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(buf.String(), "\n\nplugins:\n\tfail\t(tool-fail)\n\thello\t(tool-hello)") {
+		t.Fatalf("%q", buf.String())
+	}
+}
+
+func TestPluginsWriteToPath(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `exit 0`)
+
+	t.Setenv("PATH", dir)
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-"})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := grp.WriteTo(&buf); err != nil || strings.Contains(buf.String(), "plugins:") {
+		t.Fatal(err, buf.String())
+	}
+
+	if names := grp.Plugins(); len(names) != 1 || names[0] != "hello" {
+		t.Fatal(names)
+	}
+}
+
+func TestPluginsWriteToPathAllowList(t *testing.T) {
+	dir := t.TempDir()
+
+	writePlugin(t, dir, "tool-hello", `exit 0`)
+
+	t.Setenv("PATH", dir)
+
+	grp := new(Group).EnablePlugins(Plugins{Prefix: "tool-", Allow: []string{"hello"}})
+
+	if err := NewCommand("show the version").AddConstant("tool", false).AddConstant("version", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := grp.WriteTo(&buf); err != nil || !strings.Contains(buf.String(), "plugins:\n\thello") {
+		t.Fatal(err, buf.String())
+	}
+}
+
+func TestPluginsWithoutPrefix(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.FailNow()
+		}
+	}()
+
+	new(Group).EnablePlugins(Plugins{})
+}
+
+func TestPluginsWithoutPrefixAllowed(t *testing.T) {
+	new(Group).EnablePlugins(Plugins{Allow: []string{"hello"}})
+	new(Group).EnablePlugins(Plugins{Path: []string{t.TempDir()}})
+}
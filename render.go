@@ -0,0 +1,315 @@
+package funcv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+)
+
+// Renderer writes the non-error return values
+// (outputs) of an action function
+type Renderer interface {
+	Render(w io.Writer, outputs []interface{}) error
+}
+
+// RendererFunc is a function that implements Renderer
+type RendererFunc func(w io.Writer, outputs []interface{}) error
+
+// Render the outputs using the function
+func (f RendererFunc) Render(w io.Writer, outputs []interface{}) error {
+	return f(w, outputs)
+}
+
+type outputKey struct{}
+
+type output struct {
+	w io.Writer
+	r Renderer
+}
+
+// WithOutput returns a copy of the parent context with which the
+// outputs of executed action functions are rendered by r to w
+// (see Command.ExecuteContext), without it the outputs are discarded
+func WithOutput(parent context.Context, w io.Writer, r Renderer) context.Context {
+	return context.WithValue(parent, outputKey{}, &output{w, r})
+}
+
+func outputFrom(ctx context.Context) *output {
+	o, _ := ctx.Value(outputKey{}).(*output)
+	return o
+}
+
+// render the outputs, if there are any
+func (o *output) render(outputs []interface{}) error {
+	if o == nil || o.r == nil || len(outputs) == 0 {
+		return nil
+	}
+
+	return o.r.Render(o.w, outputs)
+}
+
+// JSONRenderer writes the outputs as JSON, a single output is
+// written as is and multiple outputs are written as an array
+type JSONRenderer struct {
+	Indent string // indentation (empty for compact output)
+}
+
+// Render the outputs as JSON
+func (r *JSONRenderer) Render(w io.Writer, outputs []interface{}) error {
+	enc := json.NewEncoder(w)
+
+	if r != nil && r.Indent != "" {
+		enc.SetIndent("", r.Indent)
+	}
+
+	if len(outputs) == 1 {
+		return enc.Encode(outputs[0])
+	}
+
+	return enc.Encode(outputs)
+}
+
+// TextRenderer writes each output in its default format
+// in a separate line, slices are written item per line
+type TextRenderer struct{}
+
+// Render the outputs as text
+func (*TextRenderer) Render(w io.Writer, outputs []interface{}) error {
+	for _, output := range outputs {
+		v := reflect.ValueOf(output)
+
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			if _, err := fmt.Fprintln(w, output); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			if _, err := fmt.Fprintln(w, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// TableRenderer writes the outputs as aligned tables, slices of
+// structs (or maps) are written as a row per item with a header
+// of the fields names (or keys), a struct (or a map) is written
+// as a row per field (or key), other values are written as text
+type TableRenderer struct {
+	MinWidth int  // minimal cell width (including padding)
+	Padding  int  // cell padding (0 or less defaults to 2)
+	NoHeader bool // don't write the header of tables
+}
+
+// Render the outputs as tables
+func (r *TableRenderer) Render(w io.Writer, outputs []interface{}) error {
+	var opts TableRenderer
+
+	if r != nil {
+		opts = *r
+	}
+
+	if opts.Padding <= 0 {
+		opts.Padding = 2
+	}
+
+	tw := tabwriter.NewWriter(w, opts.MinWidth, 8, opts.Padding, ' ', 0)
+
+	for i, output := range outputs {
+		if i > 0 {
+			if _, err := fmt.Fprintln(tw); err != nil {
+				return err
+			}
+		}
+
+		if err := opts.table(tw, reflect.ValueOf(output)); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (r *TableRenderer) table(w io.Writer, v reflect.Value) error {
+	v = indirect(v)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range exportedFields(v.Type()) {
+			if _, err := fmt.Fprintf(w, "%s\t%v\n", f.Name, v.FieldByIndex(f.Index).Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		for _, k := range sortedKeys(v) {
+			if _, err := fmt.Fprintf(w, "%v\t%v\n", k.Interface(), v.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Slice, reflect.Array:
+		return r.rows(w, v)
+	case reflect.Invalid:
+		_, err := fmt.Fprintln(w, "<nil>")
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, v.Interface())
+	return err
+}
+
+func (r *TableRenderer) rows(w io.Writer, v reflect.Value) error {
+	elem := v.Type().Elem()
+
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	var header []string
+	var row func(item reflect.Value) []interface{}
+
+	switch elem.Kind() {
+	case reflect.Struct:
+		fields := exportedFields(elem)
+
+		for _, f := range fields {
+			header = append(header, f.Name)
+		}
+
+		row = func(item reflect.Value) []interface{} {
+			cells := make([]interface{}, len(fields))
+
+			for i, f := range fields {
+				if item.IsValid() {
+					cells[i] = item.FieldByIndex(f.Index).Interface()
+				}
+			}
+
+			return cells
+		}
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+
+		for i := 0; i < v.Len(); i++ {
+			if item := indirect(v.Index(i)); item.IsValid() {
+				for _, k := range item.MapKeys() {
+					keys[fmt.Sprint(k.Interface())] = k
+				}
+			}
+		}
+
+		for k := range keys {
+			header = append(header, k)
+		}
+
+		sort.Strings(header)
+
+		row = func(item reflect.Value) []interface{} {
+			cells := make([]interface{}, len(header))
+
+			for i, h := range header {
+				if item.IsValid() {
+					if cell := item.MapIndex(keys[h]); cell.IsValid() {
+						cells[i] = cell.Interface()
+					}
+				}
+			}
+
+			return cells
+		}
+	default:
+		row = func(item reflect.Value) []interface{} {
+			if !item.IsValid() {
+				return []interface{}{nil}
+			}
+
+			return []interface{}{item.Interface()}
+		}
+	}
+
+	if len(header) > 0 && !r.NoHeader {
+		cells := make([]interface{}, len(header))
+
+		for i, h := range header {
+			cells[i] = h
+		}
+
+		if err := writeRow(w, cells); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := writeRow(w, row(indirect(v.Index(i)))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeRow(w io.Writer, cells []interface{}) error {
+	for i, cell := range cells {
+		sep := "\t"
+
+		if i+1 == len(cells) {
+			sep = "\n"
+		}
+
+		if cell == nil {
+			cell = ""
+		}
+
+		if _, err := fmt.Fprintf(w, "%v%s", cell, sep); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func exportedFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	return keys
+}
@@ -0,0 +1,200 @@
+package funcv
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+type renderRow struct {
+	Name string
+	Size int
+	size int
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := new(JSONRenderer).Render(&buf, []interface{}{renderRow{"a", 1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != `{"Name":"a","Size":1}`+"\n" {
+		t.Fatal(buf.String())
+	}
+
+	buf.Reset()
+
+	if err := new(JSONRenderer).Render(&buf, []interface{}{1, "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != `[1,"x"]`+"\n" {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestTextRenderer(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := new(TextRenderer).Render(&buf, []interface{}{[]string{"a", "b"}, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "a\nb\n3\n" {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestTableRenderer(t *testing.T) {
+	var buf bytes.Buffer
+
+	rows := []*renderRow{{"a.txt", 10, 0}, nil, {"bb.txt", 200, 0}}
+
+	if err := new(TableRenderer).Render(&buf, []interface{}{rows}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "Name    Size\na.txt   10\n        \nbb.txt  200\n" {
+		t.Fatalf("%q", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := (&TableRenderer{NoHeader: true}).Render(&buf, []interface{}{[]map[string]int{{"x": 1}, {"yy": 2}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "1  \n   2\n" {
+		t.Fatalf("%q", buf.String())
+	}
+
+	buf.Reset()
+
+	if err := new(TableRenderer).Render(&buf, []interface{}{renderRow{"a", 1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "Name  a\nSize  1\n" {
+		t.Fatalf("%q", buf.String())
+	}
+}
+
+func TestExecuteOutputs(t *testing.T) {
+	var buf bytes.Buffer
+
+	c := NewCommand("").AddConstant("test", false).MustCompile()
+
+	fn := func() (int, string, error) { return 1, "x", nil }
+
+	if _, err := c.Execute([]string{"test"}, fn); err != nil || buf.Len() != 0 {
+		t.Fatal(err, buf.String())
+	}
+
+	ctx := WithOutput(context.Background(), &buf, new(TextRenderer))
+
+	if _, err := c.ExecuteContext(ctx, []string{"test"}, fn); err != nil || buf.String() != "1\nx\n" {
+		t.Fatal(err, buf.String())
+	}
+
+	failing := RendererFunc(func(w io.Writer, outputs []interface{}) error { return io.ErrShortWrite })
+
+	ctx = WithOutput(context.Background(), &buf, failing)
+
+	if _, err := c.ExecuteContext(ctx, []string{"test"}, fn); err != io.ErrShortWrite {
+		t.Fatal(err)
+	}
+}
+
+func TestGroupOutput(t *testing.T) {
+	var grp Group
+	var buf bytes.Buffer
+
+	grp.SetOutput(&buf).
+		RegisterRenderer("upper", RendererFunc(func(w io.Writer, outputs []interface{}) error {
+			_, err := io.WriteString(w, strings.ToUpper(outputs[0].(string)))
+			return err
+		})).
+		EnableOutputFlag()
+
+	if err := NewCommand("").AddConstant("test", false).ToGroup(&grp, func() string { return "out" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if grp.ExecuteFirst([]string{"test"}) != 0 || buf.String() != "out\n" {
+		t.Fatal(buf.String())
+	}
+
+	buf.Reset()
+
+	if grp.ExecuteAll([]string{"test", "--output", "upper"}) != 1 || buf.String() != "OUT" {
+		t.Fatal(buf.String())
+	}
+
+	buf.Reset()
+
+	if grp.ExecuteAll([]string{"--output", "json", "test"}) != 1 || buf.String() != "\"out\"\n" {
+		t.Fatal(buf.String())
+	}
+
+	buf.Reset()
+
+	if grp.ExecuteAll([]string{"test", "--output=upper"}) != 1 || buf.String() != "OUT" {
+		t.Fatal(buf.String())
+	}
+
+	if grp.ExecuteFirst([]string{"test", "--output", "xml"}) != -1 {
+		t.FailNow()
+	}
+
+	if grp.ExecuteFirst([]string{"test", "--output"}) != -1 {
+		t.FailNow()
+	}
+
+	if err := NewCommand("").AddConstant("echo", false).AddVariadic("words", "", new(StringConverter)).ToGroup(&grp, func(words ...string) []string { return words }); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	if grp.ExecuteFirst([]string{"echo", "a", "--output", "json"}) != 1 || buf.String() != "a\n--output\njson\n" {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestGroupOutputDisabled(t *testing.T) {
+	var grp Group
+
+	e, _, err := grp.prepare(context.Background(), []string{"test"})
+
+	if err != nil || e.output != nil {
+		t.Fatal(err, e.output)
+	}
+
+	for _, g := range []*Group{new(Group).SetOutput(io.Discard), new(Group).SetRenderer(new(JSONRenderer)), new(Group).EnableOutputFlag()} {
+		if e, _, err := g.prepare(context.Background(), []string{"test"}); err != nil || e.output == nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGroupOutputUsage(t *testing.T) {
+	var grp Group
+	var sb strings.Builder
+
+	grp.EnableOutputFlag().RegisterRenderer("yaml", new(TextRenderer))
+
+	if err := NewCommand("list").AddConstant("list", false).ToGroup(&grp, func() []string { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := grp.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := sb.String(); !strings.Contains(s, "\n\noutput:\n\t--output name\trender the outputs by json|table|text|yaml, right after the command's constants") {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,158 @@
+package funcv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Result of parsing arguments against a command
+type Result struct {
+	// N is the number of valid arguments
+	N int
+	// Values of the extracted parameters by their argument
+	// names, the value of a variadic argument is a slice
+	Values map[string]interface{}
+	// Set reports, for every flag and variable with a
+	// default value, whether its value was found in the
+	// arguments (true) or is the default (false)
+	Set map[string]bool
+	// Remaining arguments that were not extracted
+	Remaining []string
+
+	cmd    *command
+	names  []string
+	params []interface{}
+	failed Argument
+	err    error
+
+	// a Command that is implemented outside the package (cmd
+	// is nil) is executed by its ExecuteContext with the args
+	ext  Command
+	args []string
+}
+
+// Params returns the extracted parameters by their order,
+// as they are passed to an action function
+func (r *Result) Params() []interface{} {
+	return append([]interface{}(nil), r.params...)
+}
+
+// Bind returns a Binding of the given action function to the
+// extracted parameters, it returns an error if the function
+// is not compatible with the parameters, or the parsing error
+// if the arguments are not compatible with the command
+func (r *Result) Bind(fn interface{}) (*Binding, error) {
+	return r.BindContext(context.Background(), fn)
+}
+
+// BindContext is the same as Bind but with a context that is
+// passed to the action function (see Command.ExecuteContext)
+func (r *Result) BindContext(ctx context.Context, fn interface{}) (*Binding, error) {
+	return r.bind(ctx, fn, nil)
+}
+
+// bind binds the function with the values of the command's
+// registry and then of the group's registry (if any)
+func (r *Result) bind(ctx context.Context, fn interface{}, grp *Registry) (*Binding, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.cmd == nil {
+		return nil, fmt.Errorf("funcv: result has no command to bind")
+	}
+
+	vfn := reflect.ValueOf(fn)
+
+	if vfn.Kind() != reflect.Func {
+		return nil, fmt.Errorf("funcv: invalid function [%v]", vfn.Kind())
+	}
+
+	b := &binder{ctx: ctx, cmd: r.cmd, registries: []*Registry{&r.cmd.registry, grp}}
+
+	in, err := r.cmd.bind(vfn.Type(), b, r.names, r.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Binding{fn: vfn, in: in}, nil
+}
+
+// Call binds the given action function and calls it (see Bind)
+func (r *Result) Call(fn interface{}) error {
+	b, err := r.Bind(fn)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Call()
+
+	return err
+}
+
+// execute writes the deprecation warnings, calls the given action
+// function through the hooks and the middleware (see Hooks) and
+// renders its outputs (see WithOutput)
+func (r *Result) execute(ctx context.Context, e *execution, fn interface{}) error {
+	r.warn(e.warnings)
+
+	if fn == nil {
+		return nil
+	}
+
+	outputs, err := e.invoke(ctx, &Invocation{Command: r.command(), Result: r, Fn: fn}, e.handle)
+
+	if err != nil {
+		return err
+	}
+
+	return e.output.render(outputs)
+}
+
+// command returns the parsed command
+func (r *Result) command() Command {
+	if r.cmd == nil {
+		return r.ext
+	}
+
+	return r.cmd
+}
+
+// Binding of an action function to its parameters
+type Binding struct {
+	fn reflect.Value
+	in []reflect.Value
+}
+
+// Call the action function, if its last return value is an
+// error it is returned, the other return values are returned
+// as the outputs
+func (b *Binding) Call() (outputs []interface{}, err error) {
+	ret := b.fn.Call(b.in)
+
+	if l := len(ret); l > 0 && ret[l-1].Type().Implements(errorType) {
+		if last := ret[l-1]; !isNil(last) {
+			err = last.Interface().(error)
+		}
+
+		ret = ret[:l-1]
+	}
+
+	for _, v := range ret {
+		outputs = append(outputs, v.Interface())
+	}
+
+	return outputs, err
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	c := NewCommand("").
+		AddConstant("test", false).
+		AddFlag("x", "", new(StringConverter), "xxx").
+		AddParameterlessFlag("z", "", new(BooleanConverter), true, false).
+		AddVariable("v1", "", new(IntegerConverter)).
+		AddVariableWithDefault("v2", "", new(StringConverter), "v2def").
+		AddVariadic("rest", "", new(StringConverter)).
+		MustCompile()
+
+	res, err := c.Parse([]string{"test", "-z", "5"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.N != 3 || len(res.Remaining) != 0 {
+		t.Fatal(res.N, res.Remaining)
+	}
+
+	if res.Values["x"] != "xxx" || res.Values["z"] != true || res.Values["v1"] != int64(5) || res.Values["v2"] != "v2def" {
+		t.Fatal(res.Values)
+	}
+
+	if res.Set["x"] || !res.Set["z"] || res.Set["v2"] {
+		t.Fatal(res.Set)
+	}
+
+	res, err = c.Parse([]string{"test", "5", "v2", "a", "b"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rest, ok := res.Values["rest"].([]interface{}); !ok || len(rest) != 2 || !res.Set["v2"] {
+		t.Fatal(res.Values, res.Set)
+	}
+
+	if len(res.Params()) != 6 {
+		t.Fatal(res.Params())
+	}
+}
+
+func TestParseMismatch(t *testing.T) {
+	c := NewCommand("").AddConstant("test", false).AddVariable("v", "", new(IntegerConverter)).MustCompile()
+
+	res, err := c.Parse([]string{"test", "x"})
+
+	if err == nil || res.N != 1 || len(res.Remaining) != 1 || res.Remaining[0] != "x" {
+		t.Fatal(err, res)
+	}
+
+	res, err = c.Parse([]string{"test", "1", "2"})
+
+	if !errors.Is(err, ErrUnknownArgs) || res.N != 2 || len(res.Remaining) != 1 {
+		t.Fatal(err, res)
+	}
+}
+
+func TestBindCall(t *testing.T) {
+	c := NewCommand("").AddConstant("test", false).AddVariable("v", "", new(IntegerConverter)).MustCompile()
+
+	res, err := c.Parse([]string{"test", "21"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := res.Bind(func(s string) {}); err == nil {
+		t.FailNow()
+	}
+
+	b, err := res.Bind(func(v int) (int, error) {
+		return v * 2, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs, err := b.Call()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(outputs) != 1 || outputs[0] != 42 {
+		t.Fatal(outputs)
+	}
+
+	errfunc := errors.New("function error")
+
+	if err := res.Call(func(v int) error { return errfunc }); err != errfunc {
+		t.Fatal(err)
+	}
+}
+
+func TestBindMismatch(t *testing.T) {
+	c := NewCommand("").
+		AddConstant("test", false).
+		AddVariable("n", "", new(IntegerConverter)).
+		AddCheck("n positive", func(values map[string]interface{}) error {
+			if values["n"].(int64) <= 0 {
+				return errors.New("not positive")
+			}
+
+			return nil
+		}).
+		MustCompile()
+
+	for _, args := range [][]string{{"test", "-5"}, {"test", "5", "extra"}} {
+		res, perr := c.Parse(args)
+
+		if perr == nil {
+			t.Fatal(args)
+		}
+
+		called := false
+
+		if err := res.Call(func(n int) { called = true }); err != perr || called {
+			t.Fatal(args, err, called)
+		}
+
+		if _, err := res.Bind(func(n int) {}); err != perr {
+			t.Fatal(args, err)
+		}
+	}
+}
+
+// external is a Command that is implemented outside the package
+type external struct {
+	called *bool
+}
+
+func (e external) Execute(args []string, fn interface{}) (int, error) {
+	return e.ExecuteContext(context.Background(), args, fn)
+}
+
+func (e external) ExecuteContext(ctx context.Context, args []string, fn interface{}) (int, error) {
+	if _, err := e.Parse(args); err != nil {
+		return 0, err
+	}
+
+	*e.called = true
+	fn.(func())()
+
+	return 1, nil
+}
+
+func (external) Parse(args []string) (*Result, error) {
+	if len(args) != 1 || args[0] != "ext" {
+		return nil, ErrArgNotFound
+	}
+
+	return &Result{N: 1}, nil
+}
+
+func (external) WriteTo(w io.Writer) (int64, error) {
+	return 0, nil
+}
+
+func TestExternalCommand(t *testing.T) {
+	var grp Group
+	var called, action bool
+
+	grp.Add(external{&called}, func() { action = true })
+
+	if grp.ExecuteFirst([]string{"ext"}) != 0 || !called || !action {
+		t.Fatal(called, action)
+	}
+
+	called, action = false, false
+
+	if i, err := grp.ExecuteBest([]string{"ext"}); i != 0 || err != nil || !called || !action {
+		t.Fatal(i, err, called, action)
+	}
+
+	if i, err := grp.ExecuteBest([]string{"other"}); i != IndexNotFound || !errors.Is(err, ErrNoMatch) {
+		t.Fatal(i, err)
+	}
+
+	res, _ := external{}.Parse([]string{"ext"})
+
+	if _, err := res.Bind(func() {}); err == nil {
+		t.FailNow()
+	}
+}
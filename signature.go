@@ -0,0 +1,185 @@
+package funcv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// paramSpec describes a parameter an argument extracts,
+// typ is nil if the parameter's type is unknown
+type paramSpec struct {
+	name     string
+	typ      reflect.Type
+	variadic bool
+}
+
+// paramSpecer is implemented by arguments that know
+// the parameters they extract before extracting them
+type paramSpecer interface {
+	paramSpecs() []paramSpec
+}
+
+func (*constant) paramSpecs() []paramSpec {
+	return nil
+}
+
+func (v *variable) paramSpecs() []paramSpec {
+	return []paramSpec{{name: v.name, typ: outputType(v.conv)}}
+}
+
+func (v *variadic) paramSpecs() []paramSpec {
+	return []paramSpec{{name: v.name, typ: outputType(v.conv), variadic: true}}
+}
+
+func (b *flagsBuilder) paramSpecs() []paramSpec {
+	specs := make([]paramSpec, len(b.flags))
+
+	for i, name := range b.flags {
+		specs[i] = paramSpec{name: name, typ: outputType(b.converters[name])}
+	}
+
+	return specs
+}
+
+// specs returns the parameters the command extracts, ok
+// is false if an argument doesn't describe its parameters
+func (c *command) specs() (specs []paramSpec, ok bool) {
+	for _, arg := range c.args {
+		ps, ok := arg.(paramSpecer)
+
+		if !ok {
+			return nil, false
+		}
+
+		specs = append(specs, ps.paramSpecs()...)
+	}
+
+	return specs, true
+}
+
+// checkFunc returns an error if the action function is not
+// compatible with the command's arguments
+func (c *command) checkFunc(fn interface{}, b *binder) error {
+	if fn == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(fn)
+
+	if t.Kind() != reflect.Func {
+		return fmt.Errorf("funcv: invalid function [%v]", t.Kind())
+	}
+
+	if err := checkReturns(t); err != nil {
+		return err
+	}
+
+	idx, err := b.argIndexes(t)
+
+	if err != nil {
+		return err
+	}
+
+	if c.structType != nil && len(idx) == 1 && !t.IsVariadic() && isStructParam(t.In(idx[0]), c.structType) {
+		return nil
+	}
+
+	specs, ok := c.specs()
+
+	if !ok {
+		return nil
+	}
+
+	return checkParams(t, idx, specs)
+}
+
+// checkReturns returns an error if the function returns an
+// error that is not its last return value, the other return
+// values are outputs (see Renderer)
+func checkReturns(t reflect.Type) error {
+	for i := 0; i < t.NumOut()-1; i++ {
+		if t.Out(i) == errorType {
+			return fmt.Errorf("funcv: invalid function returns %v, expected error as the last return value", t)
+		}
+	}
+
+	return nil
+}
+
+// checkParams returns an error if the function parameters at the
+// idx indexes can't receive the parameters described by specs
+func checkParams(t reflect.Type, idx []int, specs []paramSpec) error {
+	var fixed []paramSpec
+	var rest *paramSpec
+
+	for i := range specs {
+		if specs[i].variadic {
+			rest = &specs[i]
+		} else {
+			fixed = append(fixed, specs[i])
+		}
+	}
+
+	count := len(idx)
+
+	if t.IsVariadic() {
+		count--
+	}
+
+	if rest != nil && !t.IsVariadic() {
+		return fmt.Errorf("funcv: variadic argument %s requires a variadic function", rest.name)
+	}
+
+	if count > len(fixed) || !t.IsVariadic() && count < len(fixed) {
+		return fmt.Errorf("funcv: invalid function params count [count: %d, input: %d]", len(idx), len(fixed))
+	}
+
+	for i, spec := range fixed {
+		var pi int
+		var pt reflect.Type
+
+		if i < count {
+			pi = idx[i]
+			pt = t.In(pi)
+		} else {
+			pi = idx[count]
+			pt = t.In(pi).Elem()
+		}
+
+		if !compatibleTypes(spec.typ, pt) {
+			if pt.Kind() == reflect.Interface && pt.NumMethod() > 0 {
+				return fmt.Errorf("funcv: function param %d (%v) has no provider and is incompatible with argument %s (%v)", pi, pt, spec.name, spec.typ)
+			}
+
+			return fmt.Errorf("funcv: function param %d (%v) is incompatible with argument %s (%v)", pi, pt, spec.name, spec.typ)
+		}
+	}
+
+	if rest != nil {
+		if pi := idx[count]; !compatibleTypes(rest.typ, t.In(pi).Elem()) {
+			return fmt.Errorf("funcv: function param %d (...%v) is incompatible with argument %s (%v)", pi, t.In(pi).Elem(), rest.name, rest.typ)
+		}
+	}
+
+	return nil
+}
+
+// compatibleTypes returns true if a value of type src can be
+// converted to dst, an unknown (nil) src is always compatible
+func compatibleTypes(src, dst reflect.Type) bool {
+	switch {
+	case src == nil || src.Kind() == reflect.Interface:
+		return true
+	case (isInt(src.Kind()) || isUint(src.Kind())) && dst.Kind() == reflect.String:
+		// an integer is converted to a rune's text
+		return false
+	case src.ConvertibleTo(dst):
+		return true
+	case src.Kind() == reflect.Slice && dst.Kind() == reflect.Slice:
+		return compatibleTypes(src.Elem(), dst.Elem())
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package funcv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileFor(t *testing.T) {
+	b := func() Builder {
+		return NewCommand("").
+			AddConstant("test", false).
+			AddFlag("x", "", new(StringConverter), "xxx").
+			AddVariable("v", "", new(IntegerConverter))
+	}
+
+	valid := []interface{}{
+		nil,
+		func(x string, v int) {},
+		func(x string, v uint8) error { return nil },
+		func(x ...interface{}) {},
+		func(x string, v ...float64) {},
+		func(x string, v int) int { return 0 },
+		func(x string, v int) (string, int, error) { return "", 0, nil },
+	}
+
+	for _, fn := range valid {
+		if _, err := b().CompileFor(fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	invalid := map[string]interface{}{
+		"func":       "",
+		"count":      func(x string) {},
+		"count: 3":   func(x string, v int, z int) {},
+		"param 1":    func(x string, v bool) {},
+		"returns":    func(x string, v int) (error, int) { return nil, 0 },
+		"argument v": func(x string, v ...bool) {},
+	}
+
+	for msg, fn := range invalid {
+		if _, err := b().CompileFor(fn); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatal(msg, err)
+		}
+	}
+}
+
+func TestCompileForVariadic(t *testing.T) {
+	b := func() Compiler {
+		return NewCommand("").AddVariable("v", "", new(IntegerConverter)).AddVariadic("rest", "", new(IntegerConverter))
+	}
+
+	if _, err := b().CompileFor(func(v int, rest ...int) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b().CompileFor(func(rest ...int) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b().CompileFor(func(v, rest int) {}); err == nil {
+		t.FailNow()
+	}
+
+	if _, err := b().CompileFor(func(v int, rest ...string) {}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCompileForStruct(t *testing.T) {
+	if _, err := NewStructCommand("", deleteOpts{}).CompileFor(func(o *deleteOpts) {}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestToGroupSignature(t *testing.T) {
+	var grp Group
+
+	err := NewCommand("").AddConstant("test", false).AddVariable("name", "", new(StringConverter)).ToGroup(&grp, func(n int) {})
+
+	if err == nil || !strings.Contains(err.Error(), "name") {
+		t.Fatal(err)
+	}
+
+	if grp.Len() != 0 {
+		t.Fatal("command added")
+	}
+}
+
+func TestAddChecked(t *testing.T) {
+	var grp Group
+
+	cmd := NewCommand("").AddConstant("test", false).AddFlag("count", "", new(IntegerConverter), 0).MustCompile()
+
+	if err := grp.AddChecked(cmd, func(n bool) {}); err == nil || !strings.Contains(err.Error(), "argument count ") {
+		t.Fatal(err)
+	}
+
+	if grp.Len() != 0 {
+		t.Fatal("command added")
+	}
+
+	if err := grp.AddChecked(cmd, func(n int) {}); err != nil || grp.Len() != 1 {
+		t.Fatal(err, grp.Len())
+	}
+}
@@ -0,0 +1,331 @@
+package funcv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type structField struct {
+	index    []int
+	variadic bool
+}
+
+type fieldTag struct {
+	kind    string
+	options map[string]string
+}
+
+// parseTag parses a `funcv:"kind,key=value,..."` tag, a part
+// without a key continues the previous value (ex: a comma
+// inside a description)
+func parseTag(tag string) fieldTag {
+	parts := strings.Split(tag, ",")
+	ft := fieldTag{kind: strings.TrimSpace(parts[0]), options: make(map[string]string)}
+
+	var last string
+
+	for _, part := range parts[1:] {
+		key, value, found := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+
+		switch {
+		case found:
+			ft.options[key] = value
+			last = key
+		case key == "insensitive":
+			ft.options[key] = "true"
+			last = ""
+		case last != "":
+			ft.options[last] += "," + part
+		default:
+			ft.options[key] = ""
+		}
+	}
+
+	return ft
+}
+
+// converterFor returns a converter for values of the given type
+func converterFor(t reflect.Type, sep string) (Converter, error) {
+	if t == reflect.TypeOf(Range{}) {
+		return new(RangeConverter), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return new(StringConverter), nil
+	case reflect.Bool:
+		return new(BooleanConverter), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &IntegerConverter{BitSize: t.Bits()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &IntegerConverter{BitSize: t.Bits(), Unsigned: true}, nil
+	case reflect.Float32, reflect.Float64:
+		return &FloatConverter{BitSize: t.Bits()}, nil
+	case reflect.Slice:
+		elem, err := converterFor(t.Elem(), "")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &ListConverter{Elem: elem, Separator: sep}, nil
+	}
+
+	return nil, fmt.Errorf("funcv: no converter for type %v", t)
+}
+
+// NewStructCommand returns a compiler of a command that is defined
+// by the fields of the given struct (or pointer to struct), each
+// field with a funcv tag is added to the command by its order:
+//
+//	_       struct{} `funcv:"const,text=delete,insensitive"`
+//	Recycle bool     `funcv:"flag,name=r,desc=move to recycle bin"`
+//	Name    string   `funcv:"var,desc=file to delete"`
+//	Backup  string   `funcv:"var,desc=backup name,default=tmp"`
+//	Tags    []string `funcv:"variadic,desc=tags"`
+//
+// converters are inferred from the fields types (slices of flags and
+// variables use a ListConverter, sep=x sets the separator), boolean
+// flags are parameterless, the name of a field defaults to its name
+// in lower case, the action function of such command can receive the
+// struct (or a pointer to it) with the fields set to the extracted
+// values instead of the separate parameters
+func NewStructCommand(desc string, v interface{}) Compiler {
+	c := &command{desc: desc}
+
+	t := reflect.TypeOf(v)
+
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		c.err = fmt.Errorf("funcv: %v is not a struct", t)
+		return c
+	}
+
+	var cur interface{} = c
+
+	// 0: builder, 1: after an optional variable, 2: after a variadic
+	stage := 0
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("funcv")
+
+		if !ok || tag == "-" {
+			continue
+		}
+
+		ft := parseTag(tag)
+
+		if ft.kind != "const" && !f.IsExported() {
+			c.err = fmt.Errorf("funcv: field %s is not exported", f.Name)
+			return c
+		}
+
+		name, found := ft.options["name"]
+
+		if !found {
+			name = strings.ToLower(f.Name)
+		}
+
+		_, optional := ft.options["default"]
+
+		var err error
+
+		switch {
+		case stage == 2 || stage == 1 && ft.kind != "variadic" && !(ft.kind == "var" && optional):
+			err = fmt.Errorf("funcv: %s after optional arguments", ft.kind)
+		case ft.kind == "variadic":
+			stage = 2
+		case ft.kind == "var" && optional:
+			stage = 1
+		}
+
+		if err != nil {
+			c.err = fmt.Errorf("funcv: invalid field %s (%w)", f.Name, err)
+			return c
+		}
+
+		switch ft.kind {
+		case "const":
+			cur, err = addStructConst(cur, f, ft)
+		case "flag":
+			cur, err = addStructFlag(cur, f, ft, name)
+		case "var":
+			cur, err = addStructVar(cur, f, ft, name)
+		case "variadic":
+			cur, err = addStructVariadic(cur, f, ft, name)
+		default:
+			err = fmt.Errorf("funcv: unknown kind %q", ft.kind)
+		}
+
+		if err != nil {
+			c.err = fmt.Errorf("funcv: invalid field %s (%w)", f.Name, err)
+			return c
+		}
+
+		if c.err != nil {
+			return c
+		}
+
+		if ft.kind != "const" {
+			c.fields = append(c.fields, structField{index: f.Index, variadic: ft.kind == "variadic"})
+		}
+	}
+
+	c.structType = t
+
+	return cur.(Compiler)
+}
+
+func addStructConst(cur interface{}, f reflect.StructField, ft fieldTag) (interface{}, error) {
+	b := cur.(ConstantAdder)
+
+	text := ft.options["text"]
+
+	if text == "" {
+		text = strings.ToLower(f.Name)
+	}
+
+	_, insensitive := ft.options["insensitive"]
+
+	return b.AddConstant(text, insensitive), nil
+}
+
+func addStructFlag(cur interface{}, f reflect.StructField, ft fieldTag, name string) (interface{}, error) {
+	b := cur.(FlagAdder)
+
+	conv, err := converterFor(f.Type, ft.options["sep"])
+
+	if err != nil {
+		return cur, err
+	}
+
+	def, err := structDefault(f, ft, conv)
+
+	if err != nil {
+		return cur, err
+	}
+
+	if f.Type.Kind() == reflect.Bool {
+		return b.AddParameterlessFlag(name, ft.options["desc"], conv, !def.(bool), def), nil
+	}
+
+	return b.AddFlag(name, ft.options["desc"], conv, def), nil
+}
+
+func addStructVar(cur interface{}, f reflect.StructField, ft fieldTag, name string) (interface{}, error) {
+	conv, err := converterFor(f.Type, ft.options["sep"])
+
+	if err != nil {
+		return cur, err
+	}
+
+	if _, found := ft.options["default"]; found {
+		b := cur.(DefaultVariableAdder)
+
+		def, err := structDefault(f, ft, conv)
+
+		if err != nil {
+			return cur, err
+		}
+
+		return b.AddVariableWithDefault(name, ft.options["desc"], conv, def), nil
+	}
+
+	b := cur.(VariableAdder)
+
+	return b.AddVariable(name, ft.options["desc"], conv), nil
+}
+
+func addStructVariadic(cur interface{}, f reflect.StructField, ft fieldTag, name string) (interface{}, error) {
+	b := cur.(VariadicAdder)
+
+	if f.Type.Kind() != reflect.Slice {
+		return cur, fmt.Errorf("funcv: variadic field type %v is not a slice", f.Type)
+	}
+
+	conv, err := converterFor(f.Type.Elem(), "")
+
+	if err != nil {
+		return cur, err
+	}
+
+	return b.AddVariadic(name, ft.options["desc"], conv), nil
+}
+
+// structDefault returns the field's default value, converted from
+// the default tag option or the field type's zero value
+func structDefault(f reflect.StructField, ft fieldTag, conv Converter) (interface{}, error) {
+	def, found := ft.options["default"]
+
+	if !found {
+		return reflect.Zero(f.Type).Interface(), nil
+	}
+
+	v, err := conv.Convert(def)
+
+	if err != nil {
+		return nil, fmt.Errorf("funcv: invalid default %q (%w)", def, err)
+	}
+
+	return v, nil
+}
+
+// isStructParam returns true if the parameter's type
+// is the struct type or a pointer to it
+func isStructParam(in, t reflect.Type) bool {
+	return in == t || in.Kind() == reflect.Ptr && in.Elem() == t
+}
+
+// structParam sets the extracted parameters to the fields of a
+// new struct that is converted to the function's parameter type
+func (c *command) structParam(in reflect.Type, names []string, params []interface{}) (reflect.Value, error) {
+	s := reflect.New(c.structType)
+
+	j := 0
+
+	for _, sf := range c.fields {
+		f := s.Elem().FieldByIndex(sf.index)
+
+		if !sf.variadic {
+			if j >= len(params) {
+				return s, fmt.Errorf("funcv: missing param for field %s", c.structType.FieldByIndex(sf.index).Name)
+			}
+
+			v, err := convertParam(reflect.ValueOf(params[j]), f.Type())
+
+			if err != nil {
+				return s, fmt.Errorf("funcv: param %s: %w", names[j], err)
+			}
+
+			f.Set(v)
+			j++
+			continue
+		}
+
+		list := reflect.MakeSlice(f.Type(), 0, len(params)-j)
+
+		for ; j < len(params); j++ {
+			v, err := convertParam(reflect.ValueOf(params[j]), f.Type().Elem())
+
+			if err != nil {
+				return s, fmt.Errorf("funcv: param %s: %w", names[j], err)
+			}
+
+			list = reflect.Append(list, v)
+		}
+
+		f.Set(list)
+	}
+
+	if in.Kind() == reflect.Ptr {
+		return s, nil
+	}
+
+	return s.Elem(), nil
+}
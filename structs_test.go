@@ -0,0 +1,108 @@
+package funcv
+
+import (
+	"strings"
+	"testing"
+)
+
+type deleteOpts struct {
+	_       struct{} `funcv:"const,text=delete"`
+	Recycle bool     `funcv:"flag,name=r,desc=move to recycle bin"`
+	Retries uint8    `funcv:"flag,desc=retries, per file,default=3"`
+	Name    string   `funcv:"var,desc=file to delete"`
+	Backup  string   `funcv:"var,desc=backup name,default=tmp"`
+	Tags    []string `funcv:"variadic,desc=tags"`
+	ignored int
+}
+
+func TestStructCommand(t *testing.T) {
+	c := NewStructCommand("delete a file", deleteOpts{}).MustCompile()
+
+	var opts deleteOpts
+
+	_, err := c.Execute([]string{"delete", "-r", "--retries", "5", "song.mp3"}, func(o deleteOpts) {
+		opts = o
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !opts.Recycle || opts.Retries != 5 || opts.Name != "song.mp3" || opts.Backup != "tmp" || len(opts.Tags) != 0 {
+		t.Fatal("wrong values", opts)
+	}
+
+	_, err = c.Execute([]string{"delete", "song.mp3", "bak", "a", "b"}, func(o *deleteOpts) {
+		opts = *o
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.Recycle || opts.Retries != 3 || opts.Backup != "bak" || len(opts.Tags) != 2 || opts.Tags[1] != "b" {
+		t.Fatal("wrong values", opts)
+	}
+}
+
+func TestStructCommandParams(t *testing.T) {
+	c := NewStructCommand("", deleteOpts{}).MustCompile()
+
+	_, err := c.Execute([]string{"delete", "song.mp3"}, func(r bool, retries uint8, name, backup string, tags ...string) {
+		if r || retries != 3 || name != "song.mp3" || backup != "tmp" {
+			t.Fatal("wrong values", r, retries, name, backup)
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStructCommandUsage(t *testing.T) {
+	c0 := NewStructCommand("delete a file", &deleteOpts{}).MustCompile()
+
+	c1 := NewCommand("delete a file").
+		AddConstant("delete", false).
+		AddParameterlessFlag("r", "move to recycle bin", new(BooleanConverter), true, false).
+		AddFlag("retries", "retries, per file", &IntegerConverter{BitSize: 8, Unsigned: true}, int64(3)).
+		AddVariable("name", "file to delete", new(StringConverter)).
+		AddVariableWithDefault("backup", "backup name", new(StringConverter), "tmp").
+		AddVariadic("tags", "tags", new(StringConverter)).
+		MustCompile()
+
+	var sb0, sb1 strings.Builder
+
+	if _, err := c0.WriteTo(&sb0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c1.WriteTo(&sb1); err != nil {
+		t.Fatal(err)
+	}
+
+	if sb0.String() != sb1.String() {
+		t.Fatal(sb0.String(), "\n!=\n", sb1.String())
+	}
+}
+
+func TestStructCommandErrors(t *testing.T) {
+	type badType struct {
+		X chan int `funcv:"var"`
+	}
+
+	type badOrder struct {
+		X string `funcv:"var,default=x"`
+		Y string `funcv:"var"`
+	}
+
+	type badDefault struct {
+		X int `funcv:"flag,default=x"`
+	}
+
+	for _, v := range []interface{}{badType{}, badOrder{}, badDefault{}, 5} {
+		if _, err := NewStructCommand("", v).Compile(); err == nil {
+			t.Fatal(v)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package funcv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestionDistance is the maximal edit distance
+// of suggestions when it is not set (see SetSuggestionDistance)
+const DefaultSuggestionDistance = 2
+
+// Suggestion of registered constants or flags for a mistyped token
+type Suggestion struct {
+	Pos        int      // the token's position in the arguments
+	Token      string   // the mistyped token
+	Flag       bool     // the token looks like a flag
+	Candidates []string // the closest constants or flags, closest first
+}
+
+func (s *Suggestion) String() string {
+	if s.Flag {
+		return fmt.Sprintf("unknown flag %q, did you mean %s?", s.Token, quoteAll(s.Candidates))
+	}
+
+	return fmt.Sprintf("unknown command %q, did you mean %s?", s.Token, quoteAll(s.Candidates))
+}
+
+// quoteAll returns the quoted words separated by " or "
+func quoteAll(words []string) string {
+	quoted := make([]string, len(words))
+
+	for i, w := range words {
+		quoted[i] = fmt.Sprintf("%q", w)
+	}
+
+	return strings.Join(quoted, " or ")
+}
+
+// SetSuggestionDistance sets the maximal edit distance between a
+// mistyped token and the constants or flags that are suggested
+// for it in the no match diagnostics (see NoMatchError), 0 or less
+// disables the suggestions
+func (g *Group) SetSuggestionDistance(d int) *Group {
+	if d <= 0 {
+		d = -1
+	}
+
+	g.suggestDistance = d
+	return g
+}
+
+// suggestionDistance returns the maximal edit distance
+// of suggestions, 0 if suggestions are disabled
+func (g *Group) suggestionDistance() int {
+	switch {
+	case g.suggestDistance < 0:
+		return 0
+	case g.suggestDistance == 0:
+		return DefaultSuggestionDistance
+	}
+
+	return g.suggestDistance
+}
+
+// constantsAt returns the constants that the group's commands expect
+// at the given position of the arguments, the positions are known up
+// to a command's first flags or variadic argument, hidden commands are
+// left out
+func (g *Group) constantsAt(pos int) []word {
+	var words []word
+
+	for _, p := range g.pairs {
+		c, ok := p.Cmd.(*command)
+
+		if !ok || c.meta.hidden {
+			continue
+		}
+
+		for i, arg := range c.args {
+			if _, ok := arg.(*variable); ok && i < pos {
+				continue
+			}
+
+			if ct, ok := arg.(*constant); ok {
+				if i < pos {
+					continue
+				}
+
+				if i == pos {
+					words = append(words, word{text: ct.text, insensitive: ct.insensitive})
+				}
+			}
+
+			break
+		}
+	}
+
+	return words
+}
+
+// flagNames returns the flags of the group's commands,
+// hidden commands and flags are left out
+func (g *Group) flagNames() []string {
+	var flags []string
+
+	seen := make(map[string]bool)
+
+	for _, p := range g.pairs {
+		c, ok := p.Cmd.(*command)
+
+		if !ok || c.meta.hidden {
+			continue
+		}
+
+		for _, arg := range c.args {
+			if fb, ok := arg.(*flagsBuilder); ok {
+				for _, name := range fb.flags {
+					if !seen[name] && !fb.meta[name].hidden {
+						seen[name] = true
+						flags = append(flags, name)
+					}
+				}
+			}
+		}
+	}
+
+	return flags
+}
+
+// suggest adds suggestions to the no match error, for the unknown
+// constants of the closest commands, drawn from the constants of the
+// group's commands at the same position, and for tokens that look like
+// flags but are not registered in the group, drawn from the group's flags
+func (d *diagnostics) suggest(e *NoMatchError) {
+	max := d.group.suggestionDistance()
+
+	if max == 0 {
+		return
+	}
+
+	pos := -1
+
+	for _, m := range e.Candidates {
+		if m.Reason == ReasonUnknownConstant {
+			pos = m.Pos
+		}
+	}
+
+	if pos >= 0 {
+		if candidates := closest(d.args[pos], d.group.constantsAt(pos), max); len(candidates) > 0 {
+			e.Suggestions = append(e.Suggestions, Suggestion{Pos: pos, Token: d.args[pos], Candidates: candidates})
+		}
+	}
+
+	flags := d.group.flagNames()
+	known := make(map[string]bool, len(flags))
+	words := make([]word, len(flags))
+
+	for i, name := range flags {
+		known[name] = true
+		words[i] = word{text: name}
+	}
+
+	for i, arg := range d.args {
+		name := extractFlagName(arg)
+
+		if name == "" || known[name] {
+			continue
+		}
+
+		var candidates []string
+
+		for _, name := range closest(name, words, max) {
+			candidates = append(candidates, toFlag(name))
+		}
+
+		if len(candidates) > 0 {
+			e.Suggestions = append(e.Suggestions, Suggestion{Pos: i, Token: arg, Flag: true, Candidates: candidates})
+		}
+	}
+}
+
+// word that can be suggested
+type word struct {
+	text        string
+	insensitive bool
+}
+
+// closest returns the words within the maximal edit distance from
+// the token (excluding the token itself), closest first
+func closest(token string, words []word, max int) []string {
+	var found []string
+
+	dists := make(map[string]int)
+
+	for _, w := range words {
+		a, b := token, w.text
+
+		if w.insensitive {
+			a, b = strings.ToLower(a), strings.ToLower(b)
+		}
+
+		dist := levenshtein(a, b)
+
+		if prev, seen := dists[w.text]; seen {
+			dists[w.text] = min(prev, dist)
+			continue
+		}
+
+		dists[w.text] = dist
+		found = append(found, w.text)
+	}
+
+	result := found[:0]
+
+	for _, w := range found {
+		if dist := dists[w]; dist > 0 && dist <= max {
+			result = append(result, w)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return dists[result[i]] < dists[result[j]]
+	})
+
+	return result
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,105 @@
+package funcv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := map[[2]string]int{
+		{"", ""}:               0,
+		{"delete", "delete"}:   0,
+		{"delet", "delete"}:    1,
+		{"recylce", "recycle"}: 2,
+		{"", "abc"}:            3,
+		{"kitten", "sitting"}:  3,
+	}
+
+	for c, expected := range cases {
+		if d := levenshtein(c[0], c[1]); d != expected {
+			t.Fatal(c, d)
+		}
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	grp := new(Group)
+
+	if err := NewCommand("delete a file").
+		AddConstant("example", false).
+		AddConstant("DELETE", true).
+		AddParameterlessFlag("recycle", "", new(BooleanConverter), true, false).
+		AddVariable("filename", "", new(StringConverter)).
+		ToGroup(grp, func(bool, string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("describe a file").
+		AddConstant("example", false).
+		AddConstant("describe", false).
+		AddVariable("filename", "", new(StringConverter)).
+		ToGroup(grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteBest(strings.Fields("example delet song.mp3"))
+
+	if err == nil || err.Error() != `invalid command: example delet song.mp3: unknown command "delet", did you mean "DELETE"?` {
+		t.Fatal(err)
+	}
+
+	_, err = grp.ExecuteBest(strings.Fields("example describ song.mp3"))
+
+	var nm *NoMatchError
+
+	if !errors.As(err, &nm) || len(nm.Suggestions) != 1 || strings.Join(nm.Suggestions[0].Candidates, ",") != "describe" {
+		t.Fatal(err)
+	}
+
+	_, err = grp.ExecuteBest(strings.Fields("example delete --recylce song.mp3"))
+
+	if !errors.As(err, &nm) || len(nm.Suggestions) != 1 || !nm.Suggestions[0].Flag || nm.Suggestions[0].Pos != 2 {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(err.Error(), `unknown flag "--recylce", did you mean "--recycle"?`) {
+		t.Fatal(err)
+	}
+
+	grp.SetSuggestionDistance(1)
+
+	if _, err = grp.ExecuteBest(strings.Fields("example delete --recylce song.mp3")); !errors.As(err, &nm) || len(nm.Suggestions) != 0 {
+		t.Fatal(err)
+	}
+
+	grp.SetSuggestionDistance(0)
+
+	if _, err = grp.ExecuteBest(strings.Fields("example delet song.mp3")); !errors.As(err, &nm) || len(nm.Suggestions) != 0 {
+		t.Fatal(err)
+	}
+}
+
+func TestSuggestionsAcrossGroup(t *testing.T) {
+	grp := new(Group)
+
+	if err := NewCommand("remove a file").AddConstant("example", false).AddConstant("remove", false).AddVariable("filename", "", new(StringConverter)).ToGroup(grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("delete a user").AddConstant("admin", false).AddConstant("delete", false).ToGroup(grp, func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("delete a file").AddVariable("filename", "", new(StringConverter)).AddConstant("DELETE", true).ToGroup(grp, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := grp.ExecuteBest(strings.Fields("example delet song.mp3"))
+
+	var nm *NoMatchError
+
+	if !errors.As(err, &nm) || len(nm.Suggestions) != 1 || strings.Join(nm.Suggestions[0].Candidates, ",") != "delete,DELETE" {
+		t.Fatal(err)
+	}
+}
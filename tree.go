@@ -0,0 +1,343 @@
+package funcv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Flags are the values of persistent flags by their names, an
+// action function parameter of type Flags receives the values
+// of the persistent flags of the executed command's node and of
+// its ancestors (see Node)
+type Flags map[string]interface{}
+
+var flagsType = reflect.TypeOf(Flags(nil))
+
+type flagsKey struct{}
+
+func withFlags(ctx context.Context, flags Flags) context.Context {
+	return context.WithValue(ctx, flagsKey{}, flags)
+}
+
+func flagsFrom(ctx context.Context) Flags {
+	if ctx == nil {
+		return Flags{}
+	}
+
+	if flags, ok := ctx.Value(flagsKey{}).(Flags); ok {
+		return flags
+	}
+
+	return Flags{}
+}
+
+// Node of a command tree, a node owns a constant, persistent flags
+// that apply to it and to its descendants, child nodes and a group
+// of commands that receive the arguments after the node's path:
+//
+//	root := funcv.NewTree("tool", "", false)
+//	remote := root.AddNode("remote", "manage remotes", false)
+//	funcv.NewCommand("add a remote").
+//		AddConstant("add", false).
+//		AddVariable("name", "remote name", new(funcv.StringConverter)).
+//		ToGroup(remote.Commands(), fn)
+//
+// executing the tree with "tool remote add origin" walks to the
+// remote node and executes "add origin" against its commands
+type Node struct {
+	constant *constant
+	desc     string
+	flags    *flagsBuilder
+	children []*Node
+	commands Group
+}
+
+// NewTree returns the root node of a command tree, text is
+// the root's constant (ex: the program name) or empty for none
+func NewTree(text, desc string, insensitive bool) *Node {
+	return newNode(text, desc, insensitive)
+}
+
+func newNode(text, desc string, insensitive bool) *Node {
+	n := &Node{desc: desc}
+
+	if text != "" {
+		n.constant = &constant{text: text, insensitive: insensitive}
+	}
+
+	n.flags = &flagsBuilder{
+		converters: make(map[string]Converter),
+		founddefs:  make(map[string]interface{}),
+		defaults:   make(map[string]interface{}),
+		command:    &command{}}
+
+	if text != "" && !isValidConstName(text) {
+		n.flags.command.err = fmt.Errorf("funcv: invalid node constant %q", text)
+	}
+
+	return n
+}
+
+// AddNode adds a child node with the given constant and returns it
+func (n *Node) AddNode(text, desc string, insensitive bool) *Node {
+	child := newNode(text, desc, insensitive)
+
+	if text == "" {
+		child.flags.command.err = fmt.Errorf("funcv: empty node constant")
+	}
+
+	n.children = append(n.children, child)
+	return child
+}
+
+// AddPersistentFlag adds a flag (see Builder.AddFlag) that can
+// follow the constant of the node or of any of its descendants
+func (n *Node) AddPersistentFlag(name, desc string, conv Converter, def interface{}) *Node {
+	n.flags.AddFlag(name, desc, conv, def)
+	return n
+}
+
+// AddPersistentParameterlessFlag adds a parameterless flag (see
+// Builder.AddParameterlessFlag) that can follow the constant of
+// the node or of any of its descendants
+func (n *Node) AddPersistentParameterlessFlag(name, desc string, conv Converter, found, missing interface{}) *Node {
+	n.flags.AddParameterlessFlag(name, desc, conv, found, missing)
+	return n
+}
+
+// Commands returns the group of the node's commands
+func (n *Node) Commands() *Group {
+	return &n.commands
+}
+
+// Children returns the node's child nodes
+func (n *Node) Children() []*Node {
+	return append([]*Node(nil), n.children...)
+}
+
+// Err returns the first error in building the node
+// or its descendants, or nil if there is none
+func (n *Node) Err() error {
+	if err := n.flags.command.err; err != nil {
+		return err
+	}
+
+	for _, child := range n.children {
+		if err := child.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Execute walks the tree by the constants at the start of the
+// supplied arguments, the persistent flags that follow each constant
+// are extracted, the remaining arguments, without the persistent
+// flags that follow the leaf's commands arguments, are executed
+// against the commands of the deepest matching node (see
+// Group.ExecuteFirstErr), if none of a node's commands matches the
+// walk goes back and tries the next matching sibling and then the
+// commands of the parent, the node and the index of the executed
+// command in its group are returned with the error of its action
+// function, the index is negative if no command was executed (with
+// the error of the deepest node that was tried) and the node is nil
+// if the root's constant doesn't match or the tree has errors (see Err)
+func (n *Node) Execute(args []string) (*Node, int, error) {
+	return n.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext is the same as Execute but with a context
+// that is passed to the action functions (see Group.ExecuteFirstContext)
+func (n *Node) ExecuteContext(ctx context.Context, args []string) (*Node, int, error) {
+	if err := n.Err(); err != nil {
+		return nil, IndexNotFound, err
+	}
+
+	return n.walk(ctx, args, nil, Flags{})
+}
+
+// walk executes the arguments against the node's descendants and
+// then against the node's commands, the node is nil if its constant
+// doesn't match the arguments
+func (n *Node) walk(ctx context.Context, args []string, inherited []*flagsBuilder, parent Flags) (*Node, int, error) {
+	var err error
+
+	if n.constant != nil {
+		if args, _, err = n.constant.Extract(args); err != nil {
+			return nil, IndexNotFound, err
+		}
+	}
+
+	flags := make(Flags, len(parent))
+
+	for name, v := range parent {
+		flags[name] = v
+	}
+
+	if len(n.flags.flags) > 0 {
+		inherited = append(inherited[:len(inherited):len(inherited)], n.flags)
+
+		for name, def := range n.flags.defaults {
+			flags[name] = def
+		}
+	}
+
+	if args, err = extractPersistent(args, inherited, flags); err != nil {
+		return n, IndexNotFound, err
+	}
+
+	var deepest *Node
+	var deepestErr error
+
+	for _, child := range n.children {
+		found, i, err := child.walk(ctx, args, inherited, flags)
+
+		switch {
+		case found == nil:
+			continue
+		case i >= 0 || i == IndexHandled || !errors.Is(err, ErrNoMatch):
+			return found, i, err
+		case deepest == nil:
+			deepest, deepestErr = found, err
+		}
+	}
+
+	rest, err := extractPersistentAll(args, inherited, flags)
+
+	if err != nil {
+		return n, IndexNotFound, err
+	}
+
+	i, err := n.commands.ExecuteFirstErrContext(withFlags(ctx, flags), rest)
+
+	if deepest != nil && i == IndexNotFound && errors.Is(err, ErrNoMatch) {
+		return deepest, i, deepestErr
+	}
+
+	return n, i, err
+}
+
+// extractPersistent extracts the leading persistent flags of the
+// given builders into flags and returns the remaining arguments
+func extractPersistent(args []string, builders []*flagsBuilder, flags Flags) ([]string, error) {
+	for progress := true; progress; {
+		progress = false
+
+		for _, b := range builders {
+			rest, params, set, err := b.extract(args)
+
+			if err != nil {
+				return args, err
+			}
+
+			for i, name := range b.flags {
+				if set[name] {
+					flags[name] = params[i]
+				}
+			}
+
+			if len(rest) < len(args) {
+				args = rest
+				progress = true
+			}
+		}
+	}
+
+	return args, nil
+}
+
+// extractPersistentAll extracts the persistent flags of the given
+// builders at any position of the arguments into flags and
+// returns the remaining arguments
+func extractPersistentAll(args []string, builders []*flagsBuilder, flags Flags) ([]string, error) {
+	var rest []string
+
+	for len(args) > 0 {
+		left, err := extractPersistent(args, builders, flags)
+
+		if err != nil {
+			return args, err
+		}
+
+		if len(left) == 0 {
+			break
+		}
+
+		rest, args = append(rest, left[0]), left[1:]
+	}
+
+	return rest, nil
+}
+
+// WriteTo will write to the writer an informative usage text
+// about the commands in the tree, by the nodes paths
+func (n *Node) WriteTo(w io.Writer) (int64, error) {
+	return n.writeTo(w, nil)
+}
+
+func (n *Node) writeTo(w io.Writer, path []string) (int64, error) {
+	var written int64
+
+	if n.constant != nil {
+		path = append(path, n.constant.String())
+	}
+
+	header := strings.Join(path, " ")
+
+	if len(n.flags.flags) > 0 {
+		header = strings.TrimSpace(header + " " + n.flags.String())
+	}
+
+	if n.desc != "" {
+		header = fmt.Sprintf("%s:\t> %s", n.desc, header)
+	} else {
+		header = "\t> " + header
+	}
+
+	if nw, err := fmt.Fprint(w, header); err == nil {
+		written += int64(nw)
+	} else {
+		return written + int64(nw), err
+	}
+
+	if nw, err := n.flags.WriteTo(w); err == nil {
+		written += nw
+	} else {
+		return written + nw, err
+	}
+
+	if n.commands.Len() > 0 {
+		if nw, err := fmt.Fprint(w, "\n\n"); err == nil {
+			written += int64(nw)
+		} else {
+			return written + int64(nw), err
+		}
+
+		if nw, err := n.commands.WriteTo(w); err == nil {
+			written += nw
+		} else {
+			return written + nw, err
+		}
+	}
+
+	for _, child := range n.children {
+		if nw, err := fmt.Fprint(w, "\n\n"); err == nil {
+			written += int64(nw)
+		} else {
+			return written + int64(nw), err
+		}
+
+		if nw, err := child.writeTo(w, path); err == nil {
+			written += nw
+		} else {
+			return written + nw, err
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,215 @@
+package funcv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	var called string
+	var flags Flags
+
+	root := NewTree("tool", "a tool", false).
+		AddPersistentParameterlessFlag("v", "verbose", new(BooleanConverter), true, false)
+
+	remote := root.AddNode("remote", "manage remotes", true).
+		AddPersistentFlag("timeout", "timeout", new(IntegerConverter), 10)
+
+	if err := NewCommand("add a remote").
+		AddConstant("add", false).
+		AddVariable("name", "remote name", new(StringConverter)).
+		ToGroup(remote.Commands(), func(f Flags, name string) {
+			called = "add " + name
+			flags = f
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := root.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	node, i, err := root.Execute([]string{"tool", "-v", "REMOTE", "--timeout", "5", "add", "origin"})
+
+	if node != remote || i != 0 || err != nil || called != "add origin" {
+		t.Fatal(node, i, err, called)
+	}
+
+	if flags["v"] != true || flags["timeout"] != int64(5) {
+		t.Fatal(flags)
+	}
+}
+
+func TestTreePersistentFlagsAfterArgs(t *testing.T) {
+	var flags Flags
+
+	root := NewTree("tool", "", false).
+		AddPersistentParameterlessFlag("v", "verbose", new(BooleanConverter), true, false)
+
+	remote := root.AddNode("remote", "", false).
+		AddPersistentFlag("timeout", "timeout", new(IntegerConverter), 10)
+
+	if err := NewCommand("list remotes").
+		AddConstant("list", false).
+		ToGroup(remote.Commands(), func(f Flags) {
+			flags = f
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, i, err := root.Execute([]string{"tool", "remote", "list", "-v"}); node != remote || i != 0 || err != nil {
+		t.Fatal(node, i, err)
+	}
+
+	if flags["v"] != true || flags["timeout"] != 10 {
+		t.Fatal(flags)
+	}
+}
+
+func TestTreeRootCommand(t *testing.T) {
+	var flags Flags
+
+	root := NewTree("tool", "", false).
+		AddPersistentParameterlessFlag("v", "verbose", new(BooleanConverter), true, false)
+
+	root.AddNode("remote", "", false).
+		AddPersistentFlag("timeout", "timeout", new(IntegerConverter), 10)
+
+	if err := NewCommand("show the version").
+		AddConstant("version", false).
+		ToGroup(root.Commands(), func(f Flags) {
+			flags = f
+		}); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, i, err := root.Execute([]string{"tool", "version"}); node != root || i != 0 || err != nil {
+		t.Fatal(node, i, err)
+	}
+
+	if flags["v"] != false || len(flags) != 1 {
+		t.Fatal(flags)
+	}
+}
+
+func TestTreeNoMatch(t *testing.T) {
+	root := NewTree("tool", "", false)
+
+	if err := NewCommand("list remotes").AddConstant("list", false).ToGroup(root.AddNode("remote", "", false).Commands(), func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, i, err := root.Execute([]string{"tool", "remote", "remove", "x"}); node == nil || i >= 0 || !errors.Is(err, ErrNoMatch) {
+		t.Fatal(node, i, err)
+	}
+}
+
+func TestTreeInvalidPersistentFlag(t *testing.T) {
+	root := NewTree("tool", "", false)
+
+	remote := root.AddNode("remote", "", false).
+		AddPersistentFlag("timeout", "timeout", new(IntegerConverter), 10)
+
+	if err := NewCommand("list remotes").AddConstant("list", false).ToGroup(remote.Commands(), func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, i, _ := root.Execute([]string{"tool", "remote", "--timeout", "x", "list"}); node == nil || i >= 0 {
+		t.Fatal(node, i)
+	}
+}
+
+func TestTreeOtherProgram(t *testing.T) {
+	root := NewTree("tool", "", false)
+
+	if err := NewCommand("show the version").AddConstant("version", false).ToGroup(root.Commands(), func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if node, i, err := root.Execute([]string{"other"}); node != nil || i >= 0 || err == nil {
+		t.Fatal(node, i, err)
+	}
+}
+
+func TestTreeBacktracking(t *testing.T) {
+	var called string
+
+	root := NewTree("tool", "", false)
+
+	if err := NewCommand("add a remote").AddConstant("add", false).ToGroup(root.AddNode("remote", "", false).Commands(), func() { called = "add" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("show a remote").AddConstant("show", false).ToGroup(root.AddNode("remote", "", false).Commands(), func() { called = "show" }); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("echo").AddVariadic("words", "", new(StringConverter)).ToGroup(root.Commands(), func(words ...string) { called = "echo" }); err != nil {
+		t.Fatal(err)
+	}
+
+	for args, want := range map[string]string{"tool remote add": "add", "tool remote show": "show", "tool remote other": "echo"} {
+		called = ""
+
+		if node, i, err := root.Execute(strings.Fields(args)); node == nil || i != 0 || err != nil || called != want {
+			t.Fatal(args, node, i, err, called)
+		}
+	}
+}
+
+func TestTreeErr(t *testing.T) {
+	root := NewTree("", "", false)
+	root.AddNode("sub", "", false).AddPersistentFlag("1", "", new(StringConverter), "")
+
+	if root.Err() == nil {
+		t.FailNow()
+	}
+
+	if node, i, err := root.Execute([]string{"sub"}); node != nil || i >= 0 || err == nil {
+		t.Fatal(node, i)
+	}
+}
+
+func TestTreeErrEmptyName(t *testing.T) {
+	root := NewTree("", "", false)
+	root.AddNode("", "", false)
+
+	if root.Err() == nil {
+		t.FailNow()
+	}
+}
+
+func TestTreeWriteTo(t *testing.T) {
+	root := NewTree("tool", "a tool", false).
+		AddPersistentParameterlessFlag("v", "verbose", new(BooleanConverter), true, false)
+
+	remote := root.AddNode("remote", "manage remotes", true).
+		AddPersistentFlag("timeout", "timeout", new(IntegerConverter), 10)
+
+	if err := NewCommand("add a remote").
+		AddConstant("add", false).
+		AddVariable("name", "remote name", new(StringConverter)).
+		ToGroup(remote.Commands(), func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewCommand("show the version").AddConstant("version", false).ToGroup(root.Commands(), func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := root.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := buf.String()
+
+	for _, s := range []string{"a tool:\t> tool [-v]", "manage remotes:\t> tool remote [--timeout]", "add a remote:\t> add <name>", "show the version:\t> version"} {
+		if !strings.Contains(usage, s) {
+			t.Fatal(usage)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package funcv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	_ TypedConverter[string]  = (*StringConverter)(nil)
+	_ TypedConverter[int64]   = (*IntegerConverter)(nil)
+	_ TypedConverter[bool]    = (*BooleanConverter)(nil)
+	_ TypedConverter[float64] = (*FloatConverter)(nil)
+)
+
+// TypedConverter is a Converter that produces values of
+// a single, statically known type T
+type TypedConverter[T any] interface {
+	Converter
+	// ConvertTo converts the given text argument into T
+	ConvertTo(arg string) (T, error)
+}
+
+// ConverterFunc is a function that implements TypedConverter
+type ConverterFunc[T any] func(arg string) (T, error)
+
+// Convert the given argument using the function
+func (f ConverterFunc[T]) Convert(arg string) (interface{}, error) {
+	v, err := f(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ConvertTo converts the given argument using the function
+func (f ConverterFunc[T]) ConvertTo(arg string) (T, error) {
+	return f(arg)
+}
+
+// IsSupported returns true if the given value is compatible with T
+func (ConverterFunc[T]) IsSupported(v interface{}) bool {
+	return isConvertibleTo[T](v)
+}
+
+type typedConverter[T any] struct {
+	Converter
+}
+
+func (c typedConverter[T]) ConvertTo(arg string) (T, error) {
+	var t T
+
+	v, err := c.Convert(arg)
+
+	if err != nil {
+		return t, err
+	}
+
+	rv := reflect.ValueOf(v)
+	rt := reflect.TypeOf(t)
+
+	if rt == nil {
+		// T is an interface type
+		if tv, ok := v.(T); ok {
+			return tv, nil
+		}
+
+		return t, fmt.Errorf("funcv: can't convert %v to %T", v, (*T)(nil))
+	}
+
+	if !rv.IsValid() {
+		return t, fmt.Errorf("funcv: can't convert %v to %v", v, rt)
+	}
+
+	cv, err := convertParam(rv, rt)
+
+	if err != nil {
+		return t, fmt.Errorf("funcv: %w", err)
+	}
+
+	return cv.Interface().(T), nil
+}
+
+// Typed adapts an untyped Converter to a TypedConverter[T], values
+// returned by the converter are converted to T, a value that can't
+// be converted results in an error
+func Typed[T any](conv Converter) TypedConverter[T] {
+	if tc, ok := conv.(TypedConverter[T]); ok {
+		return tc
+	}
+
+	return typedConverter[T]{conv}
+}
+
+func isConvertibleTo[T any](v interface{}) bool {
+	if _, ok := v.(T); ok {
+		return true
+	}
+
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	vt := reflect.TypeOf(v)
+
+	return vt != nil && compatibleTypes(vt, rt)
+}
+
+// WithVariable adds a variable that is converted by a TypedConverter
+func WithVariable[T any](b VariableAdder, name, desc string, conv TypedConverter[T]) Builder {
+	return b.AddVariable(name, desc, conv)
+}
+
+// WithVariableDefault adds a variable with a default value that
+// is checked against the converter's type at compile time
+func WithVariableDefault[T any](b DefaultVariableAdder, name, desc string, conv TypedConverter[T], def T) ClosingBuilder {
+	return b.AddVariableWithDefault(name, desc, conv, def)
+}
+
+// WithVariadic adds a variadic parameter that is converted
+// by a TypedConverter
+func WithVariadic[T any](b VariadicAdder, name, desc string, conv TypedConverter[T]) Compiler {
+	return b.AddVariadic(name, desc, conv)
+}
+
+// WithFlag adds a flag that requires a parameter, the default value
+// is checked against the converter's type at compile time
+func WithFlag[T any](b FlagAdder, name, desc string, conv TypedConverter[T], def T) Builder {
+	return b.AddFlag(name, desc, conv, def)
+}
+
+// WithParameterlessFlag adds a flag that doesn't require a parameter,
+// the found and missing values are checked against the converter's
+// type at compile time
+func WithParameterlessFlag[T any](b FlagAdder, name, desc string, conv TypedConverter[T], found, missing T) Builder {
+	return b.AddParameterlessFlag(name, desc, conv, found, missing)
+}
+
+// Func1 returns an action function with one typed parameter, it
+// can be used to check the action's parameter types at compile time
+// against the converters of a command built with the With* functions
+func Func1[A any](_ TypedConverter[A], fn func(A) error) interface{} {
+	return fn
+}
+
+// Func2 is the same as Func1 but for two typed parameters
+func Func2[A, B any](_ TypedConverter[A], _ TypedConverter[B], fn func(A, B) error) interface{} {
+	return fn
+}
+
+// Func3 is the same as Func1 but for three typed parameters
+func Func3[A, B, C any](_ TypedConverter[A], _ TypedConverter[B], _ TypedConverter[C], fn func(A, B, C) error) interface{} {
+	return fn
+}
+
+// outputType returns the type of the values produced by the
+// converter or nil if the type is unknown
+func outputType(conv Converter) reflect.Type {
+	if t, ok := conv.(interface{ outputType() reflect.Type }); ok {
+		return t.outputType()
+	}
+
+	m := reflect.ValueOf(conv).MethodByName("ConvertTo")
+
+	if !m.IsValid() {
+		return nil
+	}
+
+	t := m.Type()
+
+	if t.NumIn() != 1 || t.In(0).Kind() != reflect.String || t.NumOut() != 2 {
+		return nil
+	}
+
+	return t.Out(0)
+}
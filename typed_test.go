@@ -0,0 +1,101 @@
+package funcv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTypedVariable(t *testing.T) {
+	str, num := new(StringConverter), new(IntegerConverter)
+
+	c := WithVariable(WithVariable(NewCommand(""), "s", "", str), "n", "", num).MustCompile()
+
+	var s string
+	var n int64
+
+	_, err := c.Execute([]string{"xyz", "123"}, Func2(str, num, func(a string, b int64) error {
+		s, n = a, b
+		return nil
+	}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s != "xyz" || n != 123 {
+		t.Fatal("wrong values", s, n)
+	}
+}
+
+func TestTypedDefaults(t *testing.T) {
+	num := new(IntegerConverter)
+
+	b := WithFlag(NewCommand("").AddConstant("test", false), "x", "", num, 5)
+	c := WithVariableDefault(b, "v", "", new(FloatConverter), 1.5).MustCompile()
+
+	_, err := c.Execute([]string{"test"}, func(x int64, v float64) {
+		if x != 5 || v != 1.5 {
+			t.Fatal("wrong values", x, v)
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConverterFunc(t *testing.T) {
+	upper := ConverterFunc[string](func(arg string) (string, error) {
+		if arg == "" {
+			return "", ErrInvalidValue
+		}
+
+		return strings.ToUpper(arg), nil
+	})
+
+	c := WithVariadic(NewCommand("").AddConstant("test", false), "words", "", upper).MustCompile()
+
+	_, err := c.Execute([]string{"test", "a", "b"}, func(words ...string) {
+		if len(words) != 2 || words[0] != "A" || words[1] != "B" {
+			t.Fatal("wrong words", words)
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !upper.IsSupported("x") || upper.IsSupported(1.5) || upper.IsSupported(65) {
+		t.FailNow()
+	}
+}
+
+func TestTypedAdapter(t *testing.T) {
+	conv := Typed[int](new(IntegerConverter))
+
+	v, err := conv.ConvertTo("42")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v != 42 {
+		t.Fatal("wrong value", v)
+	}
+
+	if _, err := conv.ConvertTo("x"); err == nil {
+		t.FailNow()
+	}
+
+	if _, err := Typed[bool](new(StringConverter)).ConvertTo("x"); err == nil {
+		t.FailNow()
+	}
+
+	if _, err := conv.ConvertTo(""); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+	if _, err := Typed[int8](new(IntegerConverter)).ConvertTo("300"); !errors.Is(err, ErrLossyConversion) {
+		t.Fatal(err)
+	}
+}
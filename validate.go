@@ -0,0 +1,237 @@
+package funcv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Validator checks a converted argument value
+type Validator interface {
+	// Validate returns a non-nil error if the
+	// given value is invalid
+	Validate(v interface{}) error
+	// String returns a short description of the
+	// constraint (ex: "non-empty")
+	fmt.Stringer
+}
+
+type validatorFunc struct {
+	desc string
+	fn   func(v interface{}) error
+}
+
+func (f *validatorFunc) Validate(v interface{}) error {
+	return f.fn(v)
+}
+
+func (f *validatorFunc) String() string {
+	return f.desc
+}
+
+// ValidatorFunc returns a Validator that uses the given
+// function, desc describes the constraint in usage texts
+func ValidatorFunc(desc string, fn func(v interface{}) error) Validator {
+	return &validatorFunc{desc: desc, fn: fn}
+}
+
+// NonEmpty returns a Validator that rejects zero values
+// and empty strings, slices and maps
+func NonEmpty() Validator {
+	return ValidatorFunc("non-empty", func(v interface{}) error {
+		rv := reflect.ValueOf(v)
+
+		switch {
+		case !rv.IsValid():
+			return errors.New("value is empty")
+		case rv.Kind() == reflect.String || rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map:
+			if rv.Len() == 0 {
+				return errors.New("value is empty")
+			}
+		case rv.IsZero():
+			return fmt.Errorf("value %v is empty", v)
+		}
+
+		return nil
+	})
+}
+
+// Matches returns a Validator that accepts only values that
+// their string representation matches the regular expression
+func Matches(re *regexp.Regexp) Validator {
+	return ValidatorFunc(fmt.Sprintf("matches %s", re), func(v interface{}) error {
+		if s := fmt.Sprint(v); !re.MatchString(s) {
+			return fmt.Errorf("value %s doesn't match %s", s, re)
+		}
+
+		return nil
+	})
+}
+
+// OneOf returns a Validator that accepts only the given values,
+// values that can't be compared with == (ex: slices) are compared
+// deeply (see reflect.DeepEqual)
+func OneOf(values ...interface{}) Validator {
+	var sb strings.Builder
+
+	for i, value := range values {
+		if i > 0 {
+			sb.WriteString("|")
+		}
+
+		sb.WriteString(fmt.Sprint(value))
+	}
+
+	desc := sb.String()
+
+	return ValidatorFunc("one of "+desc, func(v interface{}) error {
+		rv := reflect.ValueOf(v)
+
+		for _, value := range values {
+			if reflect.DeepEqual(value, v) {
+				return nil
+			}
+
+			if ev := reflect.ValueOf(value); rv.IsValid() && ev.IsValid() && ev.Type().ConvertibleTo(rv.Type()) {
+				if cv := ev.Convert(rv.Type()); cv.Comparable() && cv.Equal(rv) {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("value %v is not one of %s", v, desc)
+	})
+}
+
+// ValidationError is returned when one or more validators rejected
+// a value, or when one or more checks of a command failed, it holds
+// all the failures
+type ValidationError struct {
+	Value  interface{} // the rejected value (the values by name for checks)
+	Errs   []error     // the failures
+	Checks []string    // the descriptions of the failed checks (nil for validators)
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errs))
+
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+
+	if len(e.Checks) > 0 {
+		return fmt.Sprintf("funcv: failed check %s (%s)", strings.Join(e.Checks, ", "), strings.Join(msgs, "; "))
+	}
+
+	return fmt.Sprintf("funcv: invalid value %v (%s)", e.Value, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the validators errors
+func (e *ValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+// Is returns true for ErrInvalidValue
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+type validated struct {
+	conv       Converter
+	validators []Validator
+}
+
+// Validated returns a converter that runs the given validators on
+// the values converted by conv, all validators run and their failures
+// are returned together in a *ValidationError
+func Validated(conv Converter, validators ...Validator) Converter {
+	return &validated{conv: conv, validators: validators}
+}
+
+func (c *validated) Convert(arg string) (interface{}, error) {
+	v, err := c.conv.Convert(arg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validate(v, c.validators); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (c *validated) IsSupported(v interface{}) bool {
+	return c.conv.IsSupported(v)
+}
+
+// Constraints returns the converter's constraints
+// followed by the validators descriptions
+func (c *validated) Constraints() string {
+	var parts []string
+
+	if cd, ok := c.conv.(ConstraintDescriber); ok {
+		if s := cd.Constraints(); s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	for _, v := range c.validators {
+		if s := v.String(); s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (c *validated) outputType() reflect.Type {
+	return outputType(c.conv)
+}
+
+func validate(v interface{}, validators []Validator) error {
+	var errs []error
+
+	for _, validator := range validators {
+		if err := validator.Validate(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Value: v, Errs: errs}
+	}
+
+	return nil
+}
+
+type check struct {
+	desc string
+	fn   func(values map[string]interface{}) error
+}
+
+// valuesByName maps parameter names to their values, a name
+// of more than one parameter (ex: variadic) maps to a slice
+func valuesByName(names []string, params []interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(names))
+	count := make(map[string]int, len(names))
+
+	for _, name := range names {
+		count[name]++
+	}
+
+	for i, name := range names {
+		if count[name] == 1 {
+			values[name] = params[i]
+			continue
+		}
+
+		list, _ := values[name].([]interface{})
+		values[name] = append(list, params[i])
+	}
+
+	return values
+}
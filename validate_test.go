@@ -0,0 +1,151 @@
+package funcv
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestValidatedVariable(t *testing.T) {
+	conv := Validated(new(StringConverter), Matches(regexp.MustCompile(`^[a-z]+$`)), OneOf("dev", "prod"))
+	c := NewCommand("").AddVariable("env", "", conv).MustCompile()
+
+	if _, err := c.Execute([]string{"prod"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.Execute([]string{"QA"}, nil)
+
+	var verr *ValidationError
+
+	if !errors.As(err, &verr) || len(verr.Errs) != 2 || !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+}
+
+func TestValidatedVariadic(t *testing.T) {
+	conv := Validated(new(IntegerConverter), OneOf(1, 2, 3))
+	c := NewCommand("").AddVariadic("n", "", conv).MustCompile()
+
+	if _, err := c.Execute([]string{"1", "3"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := c.Execute([]string{"1", "4"}, nil); err == nil || n != 1 {
+		t.Fatal(n, err)
+	}
+}
+
+func TestValidatedFlag(t *testing.T) {
+	conv := Validated(new(StringConverter), ValidatorFunc("no spaces", func(v interface{}) error {
+		if strings.Contains(v.(string), " ") {
+			return fmt.Errorf("%q contains spaces", v)
+		}
+
+		return nil
+	}))
+
+	c := NewCommand("").AddFlag("name", "", conv, "x").MustCompile()
+
+	if _, err := c.Execute([]string{"--name", "a b"}, nil); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Execute([]string{"--name", "ab"}, func(name string) {
+		if name != "ab" {
+			t.Fatal("wrong value", name)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNonEmpty(t *testing.T) {
+	conv := Validated(&ListConverter{}, NonEmpty())
+
+	if _, err := conv.Convert("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NonEmpty().Validate([]string{}); err == nil {
+		t.FailNow()
+	}
+
+	if err := NonEmpty().Validate(""); err == nil {
+		t.FailNow()
+	}
+
+	if err := NonEmpty().Validate(0); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestCheck(t *testing.T) {
+	c := NewCommand("").
+		AddFlag("min", "", new(IntegerConverter), int64(0)).
+		AddFlag("max", "", new(IntegerConverter), int64(10)).
+		AddCheck("min <= max", func(values map[string]interface{}) error {
+			if values["min"].(int64) > values["max"].(int64) {
+				return errors.New("min is greater than max")
+			}
+
+			return nil
+		}).
+		AddCheck("max < 100", func(values map[string]interface{}) error {
+			if values["max"].(int64) >= 100 {
+				return errors.New("max is too big")
+			}
+
+			return nil
+		}).
+		MustCompile()
+
+	if _, err := c.Execute([]string{"--min", "5"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.Execute([]string{"--min", "500", "--max", "200"}, nil)
+
+	var verr *ValidationError
+
+	if !errors.As(err, &verr) || len(verr.Errs) != 2 {
+		t.Fatal(err)
+	}
+
+	if s := err.Error(); s != "funcv: failed check min <= max, max < 100 (min is greater than max; max is too big)" {
+		t.Fatal(s)
+	}
+}
+
+func TestOneOfList(t *testing.T) {
+	c := NewCommand("").
+		AddVariable("tags", "", Validated(new(ListConverter), OneOf([]string{"a", "b"}, "c"))).
+		MustCompile()
+
+	if _, err := c.Execute([]string{"a,b"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Execute([]string{"a,c"}, nil); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+}
+
+func TestValidatorsUsage(t *testing.T) {
+	c := NewCommand("").
+		AddVariable("env", "environment", Validated(new(StringConverter), OneOf("dev", "prod"))).
+		AddCheck("env must be set", func(map[string]interface{}) error { return nil }).
+		MustCompile()
+
+	var sb strings.Builder
+
+	if _, err := c.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(sb.String(), "environment (one of dev|prod)") || !strings.Contains(sb.String(), "env must be set") {
+		t.Fatal(sb.String())
+	}
+}